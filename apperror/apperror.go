@@ -0,0 +1,108 @@
+package apperror
+
+import (
+	"errors"
+	"expiry_tracker/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type Kind string
+
+const (
+	KindNotFound        Kind = "not_found"
+	KindValidation      Kind = "validation"
+	KindConflict        Kind = "conflict"
+	KindUnauthorized    Kind = "unauthorized"
+	KindForbidden       Kind = "forbidden"
+	KindTooManyRequests Kind = "too_many_requests"
+)
+
+// Error is a domain error that carries enough information for the HTTP layer
+// to pick a status code without inspecting error strings.
+type Error struct {
+	Kind    Kind
+	Message string
+	// Fields はバリデーションエラーのフィールド名（JSON名）ごとの詳細
+	Fields map[string]model.FieldError
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NotFound(message string) error {
+	return &Error{Kind: KindNotFound, Message: message}
+}
+
+func Conflict(message string) error {
+	return &Error{Kind: KindConflict, Message: message}
+}
+
+func Unauthorized(message string) error {
+	return &Error{Kind: KindUnauthorized, Message: message}
+}
+
+func Forbidden(message string) error {
+	return &Error{Kind: KindForbidden, Message: message}
+}
+
+func TooManyRequests(message string) error {
+	return &Error{Kind: KindTooManyRequests, Message: message}
+}
+
+func Invalid(message string) error {
+	return &Error{Kind: KindValidation, Message: message}
+}
+
+const validationFailedMessage = "validation failed"
+
+// Validation wraps an error returned by a validator. ozzo validation.Errors are
+// expanded into per-field codes and messages keyed by JSON field name.
+// Internal errors (misconfigured rules) are returned unchanged.
+func Validation(err error) error {
+	var ie validation.InternalError
+	if errors.As(err, &ie) {
+		return err
+	}
+	var ve validation.Errors
+	if !errors.As(err, &ve) {
+		return &Error{Kind: KindValidation, Message: err.Error(), Err: err}
+	}
+	fields := map[string]model.FieldError{}
+	collectFieldErrors(fields, "", ve)
+	return &Error{Kind: KindValidation, Message: validationFailedMessage, Fields: fields, Err: err}
+}
+
+func collectFieldErrors(fields map[string]model.FieldError, prefix string, ve validation.Errors) {
+	for name, fieldErr := range ve {
+		if fieldErr == nil {
+			continue
+		}
+		key := prefix + name
+		var nested validation.Errors
+		var ruleErr validation.Error
+		switch {
+		case errors.As(fieldErr, &nested):
+			collectFieldErrors(fields, key+".", nested)
+		case errors.As(fieldErr, &ruleErr):
+			fields[key] = model.FieldError{Code: ruleErr.Code(), Message: ruleErr.Error()}
+		default:
+			fields[key] = model.FieldError{Code: "validation_invalid", Message: fieldErr.Error()}
+		}
+	}
+}
+
+// KindOf returns the Kind of err, or "" if err is not a domain error.
+func KindOf(err error) Kind {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Kind
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package apperror
+
+import (
+	"errors"
+	"expiry_tracker/model"
+	"expiry_tracker/validator"
+	"testing"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func TestValidation_FieldErrors(t *testing.T) {
+	err := validator.NewProductValidator().ProductValidate(model.Product{
+		Name:       "",
+		Quantity:   -1,
+		ExpiryDate: time.Now(),
+		Type:       "invalid_type",
+	})
+
+	var appErr *Error
+	if !errors.As(Validation(err), &appErr) {
+		t.Fatalf("Validation() did not return *Error")
+	}
+	if appErr.Kind != KindValidation {
+		t.Errorf("Kind = %v, want %v", appErr.Kind, KindValidation)
+	}
+	if appErr.Message != "validation failed" {
+		t.Errorf("Message = %v, want %v", appErr.Message, "validation failed")
+	}
+
+	want := map[string]model.FieldError{
+		"name":     {Code: "validation_required", Message: "name is required"},
+		"quantity": {Code: "validation_min_greater_equal_than_required", Message: "quantity must be greater than 0"},
+		"type":     {Code: "validation_in_invalid", Message: "invalid type"},
+	}
+	if len(appErr.Fields) != len(want) {
+		t.Fatalf("Fields = %v, want %v", appErr.Fields, want)
+	}
+	for field, fe := range want {
+		if appErr.Fields[field] != fe {
+			t.Errorf("Fields[%s] = %v, want %v", field, appErr.Fields[field], fe)
+		}
+	}
+}
+
+func TestValidation_NonFieldErrors(t *testing.T) {
+	t.Run("通常のエラーはメッセージのみ", func(t *testing.T) {
+		var appErr *Error
+		if !errors.As(Validation(errors.New("bad input")), &appErr) {
+			t.Fatalf("Validation() did not return *Error")
+		}
+		if appErr.Message != "bad input" || appErr.Fields != nil {
+			t.Errorf("got %+v", appErr)
+		}
+	})
+
+	t.Run("内部エラーはそのまま返す", func(t *testing.T) {
+		ie := validation.NewInternalError(errors.New("rule misconfigured"))
+		if KindOf(Validation(ie)) != "" {
+			t.Errorf("internal error should not be a domain error")
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package buildinfo
+
+import (
+	"runtime/debug"
+)
+
+// Set at build time with
+//
+//	go build -ldflags "-X expiry_tracker/buildinfo.Version=v1.2.0 \
+//	  -X expiry_tracker/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X expiry_tracker/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// When they are not set, Commit and BuildTime fall back to the VCS
+// information that go build records when run inside a git checkout.
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info describes the running binary.
+type Info struct {
+	Version   string
+	Commit    string
+	BuildTime string
+	GoVersion string
+}
+
+// Get returns the build information, with "unknown" for what is not known.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: "unknown"}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.BuildTime == "":
+				// ビルド時刻の代わりにコミット時刻を使う
+				info.BuildTime = s.Value
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
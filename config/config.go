@@ -0,0 +1,312 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// MinSecretLength is the minimum length of SECRET, which signs login tokens
+// with HS256 and derives the other token and encryption keys.
+const MinSecretLength = 32
+
+// Config is the application configuration. Each field is read from the
+// environment variable in its env tag, or from the key in its yaml tag in the
+// file named by CONFIG_FILE.
+type Config struct {
+	Secret         string        `yaml:"secret" env:"SECRET"`
+	Server         Server        `yaml:"server"`
+	Database       Database      `yaml:"database"`
+	Mail           Mail          `yaml:"mail"`
+	Oidc           Oidc          `yaml:"oidc"`
+	TotpIssuer     string        `yaml:"totp_issuer" env:"TOTP_ISSUER"`
+	RateLimit      RateLimit     `yaml:"rate_limit"`
+	Expiry         Expiry        `yaml:"expiry"`
+	NotifyInterval time.Duration `yaml:"notify_interval" env:"NOTIFY_INTERVAL"`
+}
+
+type Server struct {
+	Port int `yaml:"port" env:"PORT"`
+	// APIDomain is the domain of the auth and CSRF cookies.
+	APIDomain string `yaml:"api_domain" env:"API_DOMAIN"`
+	// FEURL is the frontend origin, used for CORS and the links in mails.
+	FEURL string `yaml:"fe_url" env:"FE_URL"`
+	// TrustProxyHeaders takes the client IP address from X-Forwarded-For.
+	TrustProxyHeaders bool `yaml:"trust_proxy_headers" env:"TRUST_PROXY_HEADERS"`
+	// ShutdownTimeout bounds how long in-flight requests and background
+	// workers are waited for on shutdown.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// MetricsToken, when set, is required as a bearer token on /metrics.
+	MetricsToken string `yaml:"metrics_token" env:"METRICS_TOKEN"`
+}
+
+type Database struct {
+	User     string `yaml:"user" env:"POSTGRES_USER"`
+	Password string `yaml:"password" env:"POSTGRES_PW"`
+	Host     string `yaml:"host" env:"POSTGRES_HOST"`
+	Port     int    `yaml:"port" env:"POSTGRES_PORT"`
+	Name     string `yaml:"name" env:"POSTGRES_DB"`
+}
+
+// Mail selects the mailer: "log" writes mails to the log, "file" writes them
+// under Dir and "smtp" delivers them.
+type Mail struct {
+	Mailer string `yaml:"mailer" env:"MAILER"`
+	From   string `yaml:"from" env:"MAIL_FROM"`
+	Dir    string `yaml:"dir" env:"MAIL_DIR"`
+	Smtp   Smtp   `yaml:"smtp"`
+}
+
+type Smtp struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+}
+
+// Oidc configures login with an OpenID Connect identity provider. It is
+// disabled when Issuer is empty.
+type Oidc struct {
+	Issuer       string   `yaml:"issuer" env:"OIDC_ISSUER"`
+	ClientID     string   `yaml:"client_id" env:"OIDC_CLIENT_ID"`
+	ClientSecret string   `yaml:"client_secret" env:"OIDC_CLIENT_SECRET"`
+	RedirectURL  string   `yaml:"redirect_url" env:"OIDC_REDIRECT_URL"`
+	Scopes       []string `yaml:"scopes" env:"OIDC_SCOPES"`
+}
+
+// RateLimit selects where attempt counters are kept with Store: "memory" for
+// a single instance, or "postgres" to share them between instances.
+type RateLimit struct {
+	Store string       `yaml:"store" env:"RATE_LIMIT_STORE"`
+	Login AttemptLimit `yaml:"login" env:"LOGIN"`
+	Auth  AttemptLimit `yaml:"auth" env:"AUTH_RATE_LIMIT"`
+}
+
+// AttemptLimit is read from <prefix>_FREE_ATTEMPTS, <prefix>_BACKOFF_BASE,
+// <prefix>_LOCKOUT and <prefix>_WINDOW, where the prefix is the env tag of
+// the field holding it.
+type AttemptLimit struct {
+	FreeAttempts int           `yaml:"free_attempts" env:"FREE_ATTEMPTS"`
+	BaseDelay    time.Duration `yaml:"backoff_base" env:"BACKOFF_BASE"`
+	MaxDelay     time.Duration `yaml:"lockout" env:"LOCKOUT"`
+	Window       time.Duration `yaml:"window" env:"WINDOW"`
+}
+
+type Expiry struct {
+	UseByWarningDays      int `yaml:"use_by_warning_days" env:"EXPIRY_WARNING_DAYS_USE_BY"`
+	BestBeforeWarningDays int `yaml:"best_before_warning_days" env:"EXPIRY_WARNING_DAYS_BEST_BEFORE"`
+	FreezerShelfLifeDays  int `yaml:"freezer_shelf_life_days" env:"FREEZER_SHELF_LIFE_DAYS"`
+	ThawedShelfLifeDays   int `yaml:"thawed_shelf_life_days" env:"THAWED_SHELF_LIFE_DAYS"`
+}
+
+// Default returns the configuration used for settings that are not given.
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:            8080,
+			FEURL:           "http://localhost:5173",
+			ShutdownTimeout: 10 * time.Second,
+		},
+		Database: Database{
+			Host: "localhost",
+			Port: 5432,
+		},
+		Mail: Mail{
+			Mailer: "log",
+			Dir:    "tmp/mail",
+			Smtp:   Smtp{Port: 587},
+		},
+		TotpIssuer: "Fresh Keeper",
+		RateLimit: RateLimit{
+			Store: "memory",
+			Login: AttemptLimit{
+				FreeAttempts: 5,
+				BaseDelay:    time.Second,
+				MaxDelay:     15 * time.Minute,
+				Window:       time.Hour,
+			},
+			Auth: AttemptLimit{
+				FreeAttempts: 20,
+				BaseDelay:    time.Second,
+				MaxDelay:     15 * time.Minute,
+				Window:       15 * time.Minute,
+			},
+		},
+		Expiry: Expiry{
+			UseByWarningDays:      1,
+			BestBeforeWarningDays: 3,
+			FreezerShelfLifeDays:  30,
+			ThawedShelfLifeDays:   1,
+		},
+		NotifyInterval: time.Hour,
+	}
+}
+
+// Load reads the configuration. Variables in .env are added to the
+// environment when the file exists, without overriding variables that are
+// already set. Settings are then taken from the defaults, the YAML file named
+// by CONFIG_FILE if any, and the environment, each overriding the previous.
+// Load does not validate the result; see Validate.
+func Load() (Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Config{}, fmt.Errorf("load .env: %w", err)
+	}
+	cfg := Default()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(b, &cfg); err != nil {
+			return Config{}, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+	if err := loadEnv(reflect.ValueOf(&cfg).Elem(), ""); err != nil {
+		return Config{}, err
+	}
+	cfg.Server.FEURL = strings.TrimSuffix(cfg.Server.FEURL, "/")
+	return cfg, nil
+}
+
+// loadEnv sets the fields of v from the environment. Nested structs without
+// an env tag share the prefix of their parent.
+func loadEnv(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("env")
+		if key != "" && prefix != "" {
+			key = prefix + "_" + key
+		}
+		fv := v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if key == "" {
+				key = prefix
+			}
+			if err := loadEnv(fv, key); err != nil {
+				return err
+			}
+			continue
+		}
+		if key == "" {
+			continue
+		}
+		s, ok := os.LookupEnv(key)
+		if !ok || s == "" {
+			continue
+		}
+		if err := setField(fv, s); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func setField(fv reflect.Value, s string) error {
+	switch fv.Interface().(type) {
+	case string:
+		fv.SetString(s)
+	case int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(n))
+	case bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+	case []string:
+		// スペース区切りのリスト（OIDC_SCOPES など）
+		fv.Set(reflect.ValueOf(strings.Fields(s)))
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
+
+// Validate reports every invalid setting, naming the environment variables.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(len(c.Secret) >= MinSecretLength, "SECRET must be at least %d characters", MinSecretLength)
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "PORT must be between 1 and 65535")
+	check(c.Server.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive")
+	u, err := url.Parse(c.Server.FEURL)
+	check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "FE_URL must be an http or https URL")
+	if err := c.Database.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	switch c.Mail.Mailer {
+	case "log", "file":
+	case "smtp":
+		check(c.Mail.Smtp.Host != "" && c.Mail.From != "", "SMTP_HOST and MAIL_FROM are required for MAILER=smtp")
+	default:
+		errs = append(errs, fmt.Errorf("MAILER must be log, file or smtp"))
+	}
+	if c.Oidc.Issuer != "" {
+		check(c.Oidc.ClientID != "" && c.Oidc.RedirectURL != "", "OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required with OIDC_ISSUER")
+	}
+
+	check(c.RateLimit.Store == "memory" || c.RateLimit.Store == "postgres", "RATE_LIMIT_STORE must be memory or postgres")
+	for _, l := range []struct {
+		prefix string
+		limit  AttemptLimit
+	}{{"LOGIN", c.RateLimit.Login}, {"AUTH_RATE_LIMIT", c.RateLimit.Auth}} {
+		prefix, limit := l.prefix, l.limit
+		check(limit.FreeAttempts >= 0, "%s_FREE_ATTEMPTS must not be negative", prefix)
+		check(limit.BaseDelay > 0 && limit.MaxDelay >= limit.BaseDelay, "%s_BACKOFF_BASE must be positive and at most %s_LOCKOUT", prefix, prefix)
+		check(limit.Window > 0, "%s_WINDOW must be positive", prefix)
+	}
+	check(c.Expiry.UseByWarningDays >= 0 && c.Expiry.BestBeforeWarningDays >= 0 &&
+		c.Expiry.FreezerShelfLifeDays >= 0 && c.Expiry.ThawedShelfLifeDays >= 0, "expiry days must not be negative")
+	check(c.NotifyInterval > 0, "NOTIFY_INTERVAL must be positive")
+	return errors.Join(errs...)
+}
+
+// Validate checks the connection settings alone, for commands that only use
+// the database.
+func (d Database) Validate() error {
+	if d.User == "" || d.Host == "" || d.Name == "" {
+		return errors.New("POSTGRES_USER, POSTGRES_HOST and POSTGRES_DB are required")
+	}
+	if d.Port <= 0 || d.Port > 65535 {
+		return errors.New("POSTGRES_PORT must be between 1 and 65535")
+	}
+	return nil
+}
+
+// DSN returns the connection URL for the database.
+func (d Database) DSN() string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(d.User, d.Password),
+		Host:     fmt.Sprintf("%s:%d", d.Host, d.Port),
+		Path:     "/" + d.Name,
+		RawQuery: "sslmode=disable",
+	}
+	return u.String()
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	// 作業ディレクトリの .env を読み込まないようにする
+	t.Chdir(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+	yaml := `
+secret: from-file
+server:
+  port: 9000
+  fe_url: https://app.example.com/
+database:
+  user: app
+  name: expiry_tracker
+rate_limit:
+  login:
+    lockout: 30m
+oidc:
+  scopes: [openid, email]
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("SECRET", testSecret)
+	t.Setenv("POSTGRES_PORT", "5434")
+	t.Setenv("LOGIN_FREE_ATTEMPTS", "3")
+	t.Setenv("TRUST_PROXY_HEADERS", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"環境変数がファイルより優先される", cfg.Secret, testSecret},
+		{"ファイルの値", cfg.Server.Port, 9000},
+		{"末尾のスラッシュを除く", cfg.Server.FEURL, "https://app.example.com"},
+		{"ファイルと既定値の組み合わせ", cfg.Database.Host, "localhost"},
+		{"環境変数の整数", cfg.Database.Port, 5434},
+		{"環境変数の真偽値", cfg.Server.TrustProxyHeaders, true},
+		{"接頭辞つきの環境変数", cfg.RateLimit.Login.FreeAttempts, 3},
+		{"ファイルの期間", cfg.RateLimit.Login.MaxDelay, 30 * time.Minute},
+		{"既定値", cfg.RateLimit.Auth.FreeAttempts, 20},
+		{"ファイルのリスト", strings.Join(cfg.Oidc.Scopes, " "), "openid email"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestLoad_InvalidEnv(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("NOTIFY_INTERVAL", "hourly")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "NOTIFY_INTERVAL") {
+		t.Errorf("Load() error = %v, want an error naming NOTIFY_INTERVAL", err)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() Config {
+		cfg := Default()
+		cfg.Secret = testSecret
+		cfg.Database.User = "app"
+		cfg.Database.Name = "expiry_tracker"
+		return cfg
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config)
+		wantErr string
+	}{
+		{
+			name:   "有効な設定",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:    "SECRET が未設定",
+			modify:  func(cfg *Config) { cfg.Secret = "" },
+			wantErr: "SECRET",
+		},
+		{
+			name:    "SECRET が短い",
+			modify:  func(cfg *Config) { cfg.Secret = "uu5pveql" },
+			wantErr: "SECRET",
+		},
+		{
+			name:    "ポート番号が範囲外",
+			modify:  func(cfg *Config) { cfg.Server.Port = 70000 },
+			wantErr: "PORT",
+		},
+		{
+			name:    "FE_URL が URL でない",
+			modify:  func(cfg *Config) { cfg.Server.FEURL = "localhost:5173" },
+			wantErr: "FE_URL",
+		},
+		{
+			name:    "データベース名が未設定",
+			modify:  func(cfg *Config) { cfg.Database.Name = "" },
+			wantErr: "POSTGRES_DB",
+		},
+		{
+			name:    "SMTP の送信元が未設定",
+			modify:  func(cfg *Config) { cfg.Mail.Mailer = "smtp"; cfg.Mail.Smtp.Host = "smtp.example.com" },
+			wantErr: "MAIL_FROM",
+		},
+		{
+			name:    "OIDC のクライアント ID が未設定",
+			modify:  func(cfg *Config) { cfg.Oidc.Issuer = "https://idp.example.com" },
+			wantErr: "OIDC_CLIENT_ID",
+		},
+		{
+			name:    "不明な RATE_LIMIT_STORE",
+			modify:  func(cfg *Config) { cfg.RateLimit.Store = "redis" },
+			wantErr: "RATE_LIMIT_STORE",
+		},
+		{
+			name:    "NOTIFY_INTERVAL が 0",
+			modify:  func(cfg *Config) { cfg.NotifyInterval = 0 },
+			wantErr: "NOTIFY_INTERVAL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want an error naming %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDatabase_DSN(t *testing.T) {
+	d := Database{User: "app", Password: "p@ss word", Host: "db", Port: 5432, Name: "expiry_tracker"}
+
+	want := "postgres://app:p%40ss%20word@db:5432/expiry_tracker?sslmode=disable"
+	if got := d.DSN(); got != want {
+		t.Errorf("DSN() = %s, want %s", got, want)
+	}
+}
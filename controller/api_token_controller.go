@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/usecase"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type IApiTokenController interface {
+	CreateApiToken(c echo.Context) error
+	GetApiTokens(c echo.Context) error
+	DeleteApiToken(c echo.Context) error
+	BearerAuth(cookieAuth echo.MiddlewareFunc) echo.MiddlewareFunc
+}
+
+type apiTokenController struct {
+	au usecase.IApiTokenUsecase
+}
+
+func NewApiTokenController(au usecase.IApiTokenUsecase) IApiTokenController {
+	return &apiTokenController{au: au}
+}
+
+func (ac *apiTokenController) CreateApiToken(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	request := model.ApiTokenRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	tokenRes, err := ac.au.CreateApiToken(request, uint(userId.(float64)))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, tokenRes)
+}
+
+func (ac *apiTokenController) GetApiTokens(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	tokensRes, err := ac.au.GetApiTokens(uint(userId.(float64)))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, tokensRes)
+}
+
+func (ac *apiTokenController) DeleteApiToken(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	id := c.Param("tokenId")
+	tokenId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid token id")
+	}
+
+	if err := ac.au.DeleteApiToken(uint(userId.(float64)), uint(tokenId)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// BearerAuth is a middleware that accepts a personal access token in the
+// Authorization header and otherwise falls back to cookieAuth. For token
+// requests it stores the same "user" claims the JWT middleware does, so
+// handlers work unchanged. Read-only tokens are limited to safe methods.
+func (ac *apiTokenController) BearerAuth(cookieAuth echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		withCookie := cookieAuth(next)
+		return func(c echo.Context) error {
+			tokenString, ok := bearerToken(c)
+			if !ok {
+				return withCookie(c)
+			}
+			token, err := ac.au.Authenticate(tokenString)
+			if err != nil {
+				return err
+			}
+			method := c.Request().Method
+			if token.Scope != model.ApiTokenScopeWrite && method != http.MethodGet && method != http.MethodHead {
+				return apperror.Forbidden("api token is read-only")
+			}
+			c.Set("user", &jwt.Token{
+				Claims: jwt.MapClaims{"user_id": float64(token.UserId), "api_token_id": float64(token.ID)},
+				Valid:  true,
+			})
+			return next(c)
+		}
+	}
+}
+
+// bearerToken returns the token from an "Authorization: Bearer" header.
+func bearerToken(c echo.Context) (string, bool) {
+	auth := c.Request().Header.Get(echo.HeaderAuthorization)
+	scheme, token, ok := strings.Cut(auth, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// IsApiTokenRequest reports whether the request authenticates with a bearer
+// token and carries no session cookie. Such requests cannot be forged by a
+// browser on another site, so they are exempt from CSRF protection.
+func IsApiTokenRequest(c echo.Context) bool {
+	if _, ok := bearerToken(c); !ok {
+		return false
+	}
+	_, err := c.Cookie(accessTokenCookie)
+	return err != nil
+}
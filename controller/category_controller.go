@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type ICategoryController interface {
+	GetAllCategories(c echo.Context) error
+	CreateCategory(c echo.Context) error
+	UpdateCategory(c echo.Context) error
+	DeleteCategory(c echo.Context) error
+}
+
+type categoryController struct {
+	cu usecase.ICategoryUsecase
+}
+
+func NewCategoryController(cu usecase.ICategoryUsecase) ICategoryController {
+	return &categoryController{cu: cu}
+}
+
+func (cc *categoryController) GetAllCategories(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+
+	categoriesRes, err := cc.cu.GetAllCategories(uint(userId.(float64)), householdId)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, categoriesRes)
+}
+
+func (cc *categoryController) CreateCategory(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+
+	category := model.Category{}
+	if err := c.Bind(&category); err != nil {
+		return err
+	}
+	category.UserId = uint(userId.(float64))
+	categoryRes, err := cc.cu.CreateCategory(category, householdId)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, categoryRes)
+}
+
+func (cc *categoryController) UpdateCategory(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+	id := c.Param("categoryId")
+	categoryId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid category id")
+	}
+
+	category := model.Category{}
+	if err := c.Bind(&category); err != nil {
+		return err
+	}
+	categoryRes, err := cc.cu.UpdateCategory(category, uint(userId.(float64)), householdId, uint(categoryId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, categoryRes)
+}
+
+func (cc *categoryController) DeleteCategory(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+	id := c.Param("categoryId")
+	categoryId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid category id")
+	}
+
+	if err := cc.cu.DeleteCategory(uint(userId.(float64)), householdId, uint(categoryId)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
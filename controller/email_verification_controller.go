@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"expiry_tracker/model"
+	"expiry_tracker/usecase"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type IEmailVerificationController interface {
+	VerifyEmail(c echo.Context) error
+	ResendVerification(c echo.Context) error
+}
+
+type emailVerificationController struct {
+	eu usecase.IEmailVerificationUsecase
+}
+
+func NewEmailVerificationController(eu usecase.IEmailVerificationUsecase) IEmailVerificationController {
+	return &emailVerificationController{eu: eu}
+}
+
+func (ec *emailVerificationController) VerifyEmail(c echo.Context) error {
+	request := model.VerifyEmailRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	if err := ec.eu.VerifyEmail(request); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (ec *emailVerificationController) ResendVerification(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	if err := ec.eu.ResendVerification(uint(userId.(float64))); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusAccepted)
+}
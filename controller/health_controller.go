@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+	"expiry_tracker/model"
+	"expiry_tracker/usecase"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// readinessTimeout keeps a hung database from holding up the probe.
+const readinessTimeout = 3 * time.Second
+
+type IHealthController interface {
+	Healthz(c echo.Context) error
+	Readyz(c echo.Context) error
+	Version(c echo.Context) error
+}
+
+type healthController struct {
+	hu usecase.IHealthUsecase
+}
+
+func NewHealthController(hu usecase.IHealthUsecase) IHealthController {
+	return &healthController{hu: hu}
+}
+
+// Healthz reports that the process is up and serving requests.
+func (hc *healthController) Healthz(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": model.HealthStatusOK})
+}
+
+// Readyz reports whether the instance can take traffic, with 503 when any
+// check fails.
+func (hc *healthController) Readyz(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), readinessTimeout)
+	defer cancel()
+
+	res := hc.hu.Ready(ctx)
+	if res.Status != model.HealthStatusOK {
+		return c.JSON(http.StatusServiceUnavailable, res)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (hc *healthController) Version(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), readinessTimeout)
+	defer cancel()
+
+	return c.JSON(http.StatusOK, hc.hu.Version(ctx))
+}
+
+// IsHealthCheck reports whether the request is for one of the probe
+// endpoints, which are called without cookies or a CSRF token.
+func IsHealthCheck(c echo.Context) bool {
+	switch c.Path() {
+	case "/healthz", "/readyz", "/version":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderHouseholdId selects the household a request acts on. Without it the
+// user's default household is used.
+const HeaderHouseholdId = "X-Household-Id"
+
+type IHouseholdController interface {
+	GetAllHouseholds(c echo.Context) error
+	GetHouseholdById(c echo.Context) error
+	CreateHousehold(c echo.Context) error
+	UpdateHousehold(c echo.Context) error
+	DeleteHousehold(c echo.Context) error
+	UpdateMemberRole(c echo.Context) error
+	RemoveMember(c echo.Context) error
+}
+
+type householdController struct {
+	hu usecase.IHouseholdUsecase
+}
+
+func NewHouseholdController(hu usecase.IHouseholdUsecase) IHouseholdController {
+	return &householdController{hu: hu}
+}
+
+func (hc *householdController) GetAllHouseholds(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	householdsRes, err := hc.hu.GetAllHouseholds(uint(userId.(float64)))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, householdsRes)
+}
+
+func (hc *householdController) GetHouseholdById(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	id := c.Param("householdId")
+	householdId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid household id")
+	}
+
+	householdRes, err := hc.hu.GetHouseholdById(uint(userId.(float64)), uint(householdId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, householdRes)
+}
+
+func (hc *householdController) CreateHousehold(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	household := model.Household{}
+	if err := c.Bind(&household); err != nil {
+		return err
+	}
+	householdRes, err := hc.hu.CreateHousehold(household, uint(userId.(float64)))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, householdRes)
+}
+
+func (hc *householdController) UpdateHousehold(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	id := c.Param("householdId")
+	householdId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid household id")
+	}
+
+	household := model.Household{}
+	if err := c.Bind(&household); err != nil {
+		return err
+	}
+	householdRes, err := hc.hu.UpdateHousehold(household, uint(userId.(float64)), uint(householdId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, householdRes)
+}
+
+func (hc *householdController) DeleteHousehold(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	id := c.Param("householdId")
+	householdId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid household id")
+	}
+
+	if err := hc.hu.DeleteHousehold(uint(userId.(float64)), uint(householdId)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (hc *householdController) UpdateMemberRole(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := strconv.Atoi(c.Param("householdId"))
+	if err != nil {
+		return apperror.Invalid("invalid household id")
+	}
+	memberId, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		return apperror.Invalid("invalid user id")
+	}
+
+	request := model.HouseholdMemberRoleRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	householdRes, err := hc.hu.UpdateMemberRole(request, uint(userId.(float64)), uint(householdId), uint(memberId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, householdRes)
+}
+
+func (hc *householdController) RemoveMember(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := strconv.Atoi(c.Param("householdId"))
+	if err != nil {
+		return apperror.Invalid("invalid household id")
+	}
+	memberId, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		return apperror.Invalid("invalid user id")
+	}
+
+	if err := hc.hu.RemoveMember(uint(userId.(float64)), uint(householdId), uint(memberId)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// householdIdFromHeader returns the household selected by HeaderHouseholdId,
+// or 0 for the user's default household.
+func householdIdFromHeader(c echo.Context) (uint, error) {
+	v := c.Request().Header.Get(HeaderHouseholdId)
+	if v == "" {
+		return 0, nil
+	}
+	householdId, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || householdId == 0 {
+		return 0, apperror.Invalid("invalid household id")
+	}
+	return uint(householdId), nil
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type IHouseholdInvitationController interface {
+	CreateInvitation(c echo.Context) error
+	GetPendingInvitations(c echo.Context) error
+	RevokeInvitation(c echo.Context) error
+	AcceptInvitation(c echo.Context) error
+	DeclineInvitation(c echo.Context) error
+}
+
+type householdInvitationController struct {
+	iu usecase.IHouseholdInvitationUsecase
+}
+
+func NewHouseholdInvitationController(iu usecase.IHouseholdInvitationUsecase) IHouseholdInvitationController {
+	return &householdInvitationController{iu: iu}
+}
+
+func (ic *householdInvitationController) CreateInvitation(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	id := c.Param("householdId")
+	householdId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid household id")
+	}
+
+	request := model.HouseholdInvitationRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	invitationRes, err := ic.iu.CreateInvitation(request, uint(userId.(float64)), uint(householdId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, invitationRes)
+}
+
+func (ic *householdInvitationController) GetPendingInvitations(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	id := c.Param("householdId")
+	householdId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid household id")
+	}
+
+	invitationsRes, err := ic.iu.GetPendingInvitations(uint(userId.(float64)), uint(householdId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, invitationsRes)
+}
+
+func (ic *householdInvitationController) RevokeInvitation(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := strconv.Atoi(c.Param("householdId"))
+	if err != nil {
+		return apperror.Invalid("invalid household id")
+	}
+	invitationId, err := strconv.Atoi(c.Param("invitationId"))
+	if err != nil {
+		return apperror.Invalid("invalid invitation id")
+	}
+
+	if err := ic.iu.RevokeInvitation(uint(userId.(float64)), uint(householdId), uint(invitationId)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (ic *householdInvitationController) AcceptInvitation(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	request := model.InvitationTokenRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	householdRes, err := ic.iu.AcceptInvitation(request, uint(userId.(float64)))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, householdRes)
+}
+
+func (ic *householdInvitationController) DeclineInvitation(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	request := model.InvitationTokenRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	if err := ic.iu.DeclineInvitation(request, uint(userId.(float64))); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/usecase"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// oidcStateCookie holds the state token between the redirect to the identity
+// provider and the callback.
+const oidcStateCookie = "oidc_state"
+
+type IOidcController interface {
+	Login(c echo.Context) error
+	Callback(c echo.Context) error
+}
+
+type oidcController struct {
+	ou           usecase.IOidcUsecase
+	feURL        string
+	cookieDomain string
+}
+
+func NewOidcController(ou usecase.IOidcUsecase, feURL string, cookieDomain string) IOidcController {
+	return &oidcController{ou: ou, feURL: feURL, cookieDomain: cookieDomain}
+}
+
+func (oc *oidcController) Login(c echo.Context) error {
+	authURL, stateToken, err := oc.ou.AuthCodeURL(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	c.SetCookie(stateCookie(oc.cookieDomain, stateToken, time.Now().Add(model.OidcStateLifetime)))
+	return c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback is where the identity provider sends the browser back. The user
+// ends up on the frontend either way: logged in, on the two-factor step, or
+// on the login page with an error.
+func (oc *oidcController) Callback(c echo.Context) error {
+	stateToken := ""
+	if cookie, err := c.Cookie(oidcStateCookie); err == nil {
+		stateToken = cookie.Value
+	}
+	c.SetCookie(stateCookie(oc.cookieDomain, "", time.Now()))
+
+	if e := c.QueryParam("error"); e != "" {
+		// ユーザーが ID プロバイダーでログインを取り消した場合など
+		return oc.redirectToLogin(c, url.Values{"error": {e}})
+	}
+	result, err := oc.ou.Callback(c.Request().Context(), c.QueryParam("code"), c.QueryParam("state"), stateToken, sessionClient(c))
+	if err != nil {
+		switch apperror.KindOf(err) {
+		case apperror.KindUnauthorized, apperror.KindForbidden, apperror.KindConflict:
+			return oc.redirectToLogin(c, url.Values{"error": {err.Error()}})
+		}
+		return err
+	}
+	if result.ChallengeToken != "" {
+		return oc.redirectToLogin(c, url.Values{"challenge_token": {result.ChallengeToken}})
+	}
+	setAuthCookies(c, oc.cookieDomain, result.Tokens)
+	return c.Redirect(http.StatusFound, oc.feURL+"/")
+}
+
+func (oc *oidcController) redirectToLogin(c echo.Context, query url.Values) error {
+	return c.Redirect(http.StatusFound, oc.feURL+"/login?"+query.Encode())
+}
+
+func stateCookie(domain string, value string, expires time.Time) *http.Cookie {
+	cookie := authCookie(domain, oidcStateCookie, value, expires)
+	cookie.Path = "/oidc"
+	// ID プロバイダーからのリダイレクト（トップレベルの GET）でも送られるようにする
+	cookie.SameSite = http.SameSiteLaxMode
+	return cookie
+}
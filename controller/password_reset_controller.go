@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"expiry_tracker/model"
+	"expiry_tracker/usecase"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type IPasswordResetController interface {
+	ForgotPassword(c echo.Context) error
+	ResetPassword(c echo.Context) error
+}
+
+type passwordResetController struct {
+	pu           usecase.IPasswordResetUsecase
+	cookieDomain string
+}
+
+func NewPasswordResetController(pu usecase.IPasswordResetUsecase, cookieDomain string) IPasswordResetController {
+	return &passwordResetController{pu: pu, cookieDomain: cookieDomain}
+}
+
+func (pc *passwordResetController) ForgotPassword(c echo.Context) error {
+	request := model.ForgotPasswordRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	if err := pc.pu.ForgotPassword(request); err != nil {
+		return err
+	}
+	// 登録の有無にかかわらず同じレスポンスを返す
+	return c.NoContent(http.StatusAccepted)
+}
+
+func (pc *passwordResetController) ResetPassword(c echo.Context) error {
+	request := model.ResetPasswordRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	if err := pc.pu.ResetPassword(request); err != nil {
+		return err
+	}
+	// 全セッションが失効するため、この端末の Cookie も消す
+	clearAuthCookies(c, pc.cookieDomain)
+	return c.NoContent(http.StatusNoContent)
+}
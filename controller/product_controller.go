@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"expiry_tracker/apperror"
 	"expiry_tracker/model"
 	"expiry_tracker/usecase"
 	"net/http"
@@ -16,6 +17,12 @@ type IProductController interface {
 	CreateProduct(c echo.Context) error
 	UpdateProduct(c echo.Context) error
 	DeleteProduct(c echo.Context) error
+	ConsumeProduct(c echo.Context) error
+	DiscardProduct(c echo.Context) error
+	GetConsumptionEvents(c echo.Context) error
+	OpenProduct(c echo.Context) error
+	FreezeProduct(c echo.Context) error
+	ThawProduct(c echo.Context) error
 }
 
 type productController struct {
@@ -30,10 +37,18 @@ func (pc *productController) GetAllProducts(c echo.Context) error {
 	user := c.Get("user").(*jwt.Token)
 	claims := user.Claims.(jwt.MapClaims)
 	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
 
-	productRes, err := pc.pu.GetAllProducts(uint(userId.(float64)))
+	query := model.ProductQuery{}
+	if err := c.Bind(&query); err != nil {
+		return err
+	}
+	productRes, err := pc.pu.GetAllProducts(uint(userId.(float64)), householdId, query)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 	return c.JSON(http.StatusOK, productRes)
 }
@@ -42,11 +57,18 @@ func (pc *productController) GetProductById(c echo.Context) error {
 	user := c.Get("user").(*jwt.Token)
 	claims := user.Claims.(jwt.MapClaims)
 	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
 	id := c.Param("productId")
-	productId, _ := strconv.Atoi(id)
-	productRes, err := pc.pu.GetProductByID(uint(userId.(float64)), uint(productId))
+	productId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid product id")
+	}
+	productRes, err := pc.pu.GetProductByID(uint(userId.(float64)), householdId, uint(productId))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 	return c.JSON(http.StatusOK, productRes)
 }
@@ -55,15 +77,19 @@ func (pc *productController) CreateProduct(c echo.Context) error {
 	user := c.Get("user").(*jwt.Token)
 	claims := user.Claims.(jwt.MapClaims)
 	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
 
 	product := model.Product{}
 	if err := c.Bind(&product); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 	product.UserId = uint(userId.(float64))
-	productRes, err := pc.pu.CreateProduct(product)
+	productRes, err := pc.pu.CreateProduct(product, householdId)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 	return c.JSON(http.StatusCreated, productRes)
 }
@@ -72,16 +98,23 @@ func (pc *productController) UpdateProduct(c echo.Context) error {
 	user := c.Get("user").(*jwt.Token)
 	claims := user.Claims.(jwt.MapClaims)
 	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
 	id := c.Param("productId")
-	productId, _ := strconv.Atoi(id)
+	productId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid product id")
+	}
 
 	product := model.Product{}
 	if err := c.Bind(&product); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
-	taskRes, err := pc.pu.UpdateProduct(product, uint(userId.(float64)), uint(productId))
+	taskRes, err := pc.pu.UpdateProduct(product, uint(userId.(float64)), householdId, uint(productId))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 	return c.JSON(http.StatusOK, taskRes)
 }
@@ -90,12 +123,164 @@ func (pc *productController) DeleteProduct(c echo.Context) error {
 	user := c.Get("user").(*jwt.Token)
 	claims := user.Claims.(jwt.MapClaims)
 	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
 	id := c.Param("productId")
-	productId, _ := strconv.Atoi(id)
-
-	err := pc.pu.DeleteProduct(uint(userId.(float64)), uint(productId))
+	productId, err := strconv.Atoi(id)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, err.Error())
+		return apperror.Invalid("invalid product id")
+	}
+
+	if err := pc.pu.DeleteProduct(uint(userId.(float64)), householdId, uint(productId)); err != nil {
+		return err
 	}
 	return c.NoContent(http.StatusNoContent)
 }
+
+func (pc *productController) ConsumeProduct(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+	id := c.Param("productId")
+	productId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid product id")
+	}
+
+	adjustment := model.QuantityAdjustment{}
+	if err := c.Bind(&adjustment); err != nil {
+		return err
+	}
+	productRes, err := pc.pu.ConsumeProduct(adjustment, uint(userId.(float64)), householdId, uint(productId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, productRes)
+}
+
+func (pc *productController) DiscardProduct(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+	id := c.Param("productId")
+	productId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid product id")
+	}
+
+	adjustment := model.QuantityAdjustment{}
+	if err := c.Bind(&adjustment); err != nil {
+		return err
+	}
+	productRes, err := pc.pu.DiscardProduct(adjustment, uint(userId.(float64)), householdId, uint(productId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, productRes)
+}
+
+func (pc *productController) GetConsumptionEvents(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+	id := c.Param("productId")
+	productId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid product id")
+	}
+
+	eventsRes, err := pc.pu.GetConsumptionEvents(uint(userId.(float64)), householdId, uint(productId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, eventsRes)
+}
+
+func (pc *productController) OpenProduct(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+	id := c.Param("productId")
+	productId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid product id")
+	}
+
+	request := model.OpenProductRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	productRes, err := pc.pu.OpenProduct(request, uint(userId.(float64)), householdId, uint(productId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, productRes)
+}
+
+func (pc *productController) FreezeProduct(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+	id := c.Param("productId")
+	productId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid product id")
+	}
+
+	request := model.FreezeProductRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	productRes, err := pc.pu.FreezeProduct(request, uint(userId.(float64)), householdId, uint(productId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, productRes)
+}
+
+func (pc *productController) ThawProduct(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+	id := c.Param("productId")
+	productId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid product id")
+	}
+
+	request := model.ThawProductRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	productRes, err := pc.pu.ThawProduct(request, uint(userId.(float64)), householdId, uint(productId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, productRes)
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"expiry_tracker/usecase"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimitByIP returns a middleware that counts every request per client IP
+// address and rejects requests while the address has to back off. It is
+// meant for unauthenticated endpoints such as login and signup.
+func RateLimitByIP(limiter usecase.IAttemptLimiter, scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := "request:" + scope + ":" + c.RealIP()
+			if err := limiter.Check(key); err != nil {
+				return err
+			}
+			if err := limiter.Record(key); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
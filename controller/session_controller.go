@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type ISessionController interface {
+	GetSessions(c echo.Context) error
+	RevokeSession(c echo.Context) error
+	VerifySession(next echo.HandlerFunc) echo.HandlerFunc
+}
+
+type sessionController struct {
+	su usecase.ISessionUsecase
+}
+
+func NewSessionController(su usecase.ISessionUsecase) ISessionController {
+	return &sessionController{su: su}
+}
+
+func (sc *sessionController) GetSessions(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	sessionId := claims["sid"]
+
+	sessionsRes, err := sc.su.GetSessions(uint(userId.(float64)), uint(sessionId.(float64)))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, sessionsRes)
+}
+
+func (sc *sessionController) RevokeSession(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	id := c.Param("sessionId")
+	sessionId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid session id")
+	}
+
+	if err := sc.su.RevokeSession(uint(userId.(float64)), uint(sessionId)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// VerifySession is a middleware that runs after the JWT middleware and
+// rejects access tokens whose session has been revoked or has expired.
+func (sc *sessionController) VerifySession(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*jwt.Token)
+		claims := user.Claims.(jwt.MapClaims)
+		userId, ok := claims["user_id"].(float64)
+		if !ok {
+			return apperror.Unauthorized("invalid token")
+		}
+		// セッション導入前に発行されたトークンは sid を持たないため再ログインさせる
+		sessionId, ok := claims["sid"].(float64)
+		if !ok {
+			return apperror.Unauthorized("session is no longer valid")
+		}
+		if err := sc.su.VerifySession(uint(userId), uint(sessionId)); err != nil {
+			return err
+		}
+		return next(c)
+	}
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"expiry_tracker/model"
+	"expiry_tracker/usecase"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type IStatsController interface {
+	GetExpiredStats(c echo.Context) error
+	GetWasteStats(c echo.Context) error
+	GetConsumptionTimeStats(c echo.Context) error
+	GetUpcomingExpiryStats(c echo.Context) error
+}
+
+type statsController struct {
+	su usecase.IStatsUsecase
+}
+
+func NewStatsController(su usecase.IStatsUsecase) IStatsController {
+	return &statsController{su: su}
+}
+
+func (sc *statsController) GetExpiredStats(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+
+	query := model.StatsQuery{}
+	if err := c.Bind(&query); err != nil {
+		return err
+	}
+	statsRes, err := sc.su.GetExpiredStats(uint(userId.(float64)), householdId, query)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, statsRes)
+}
+
+func (sc *statsController) GetWasteStats(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+
+	statsRes, err := sc.su.GetWasteStats(uint(userId.(float64)), householdId)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, statsRes)
+}
+
+func (sc *statsController) GetConsumptionTimeStats(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+
+	statsRes, err := sc.su.GetConsumptionTimeStats(uint(userId.(float64)), householdId)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, statsRes)
+}
+
+func (sc *statsController) GetUpcomingExpiryStats(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+
+	statsRes, err := sc.su.GetUpcomingExpiryStats(uint(userId.(float64)), householdId)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, statsRes)
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/usecase"
+	"net/http"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type IStorageLocationController interface {
+	GetAllStorageLocations(c echo.Context) error
+	CreateStorageLocation(c echo.Context) error
+	UpdateStorageLocation(c echo.Context) error
+	DeleteStorageLocation(c echo.Context) error
+}
+
+type storageLocationController struct {
+	lu usecase.IStorageLocationUsecase
+}
+
+func NewStorageLocationController(lu usecase.IStorageLocationUsecase) IStorageLocationController {
+	return &storageLocationController{lu: lu}
+}
+
+func (lc *storageLocationController) GetAllStorageLocations(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+
+	locationsRes, err := lc.lu.GetAllStorageLocations(uint(userId.(float64)), householdId)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, locationsRes)
+}
+
+func (lc *storageLocationController) CreateStorageLocation(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+
+	location := model.StorageLocation{}
+	if err := c.Bind(&location); err != nil {
+		return err
+	}
+	location.UserId = uint(userId.(float64))
+	locationRes, err := lc.lu.CreateStorageLocation(location, householdId)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, locationRes)
+}
+
+func (lc *storageLocationController) UpdateStorageLocation(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+	id := c.Param("locationId")
+	locationId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid storage location id")
+	}
+
+	location := model.StorageLocation{}
+	if err := c.Bind(&location); err != nil {
+		return err
+	}
+	locationRes, err := lc.lu.UpdateStorageLocation(location, uint(userId.(float64)), householdId, uint(locationId))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, locationRes)
+}
+
+func (lc *storageLocationController) DeleteStorageLocation(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	householdId, err := householdIdFromHeader(c)
+	if err != nil {
+		return err
+	}
+	id := c.Param("locationId")
+	locationId, err := strconv.Atoi(id)
+	if err != nil {
+		return apperror.Invalid("invalid storage location id")
+	}
+
+	if err := lc.lu.DeleteStorageLocation(uint(userId.(float64)), householdId, uint(locationId)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
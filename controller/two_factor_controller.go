@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"expiry_tracker/model"
+	"expiry_tracker/usecase"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+type ITwoFactorController interface {
+	Enroll(c echo.Context) error
+	Confirm(c echo.Context) error
+	Disable(c echo.Context) error
+	Login(c echo.Context) error
+}
+
+type twoFactorController struct {
+	tu           usecase.ITwoFactorUsecase
+	cookieDomain string
+}
+
+func NewTwoFactorController(tu usecase.ITwoFactorUsecase, cookieDomain string) ITwoFactorController {
+	return &twoFactorController{tu: tu, cookieDomain: cookieDomain}
+}
+
+func (tc *twoFactorController) Enroll(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	enrollRes, err := tc.tu.Enroll(uint(userId.(float64)))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, enrollRes)
+}
+
+func (tc *twoFactorController) Confirm(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	request := model.TwoFactorCodeRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	codesRes, err := tc.tu.Confirm(request, uint(userId.(float64)))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, codesRes)
+}
+
+func (tc *twoFactorController) Disable(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	request := model.TwoFactorDisableRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	if err := tc.tu.Disable(request, uint(userId.(float64))); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (tc *twoFactorController) Login(c echo.Context) error {
+	request := model.TwoFactorLoginRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	tokens, err := tc.tu.Login(request, sessionClient(c))
+	if err != nil {
+		return err
+	}
+	setAuthCookies(c, tc.cookieDomain, tokens)
+	return c.NoContent(http.StatusOK)
+}
@@ -1,39 +1,47 @@
 package controller
 
 import (
+	"expiry_tracker/apperror"
 	"expiry_tracker/model"
 	"expiry_tracker/usecase"
 	"net/http"
-	"os"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
 type IUserController interface {
 	SignUp(c echo.Context) error
 	Login(c echo.Context) error
+	RefreshToken(c echo.Context) error
 	LogOut(c echo.Context) error
 	CsrfToken(c echo.Context) error
+	GetMe(c echo.Context) error
+	UpdateProfile(c echo.Context) error
+	ChangeEmail(c echo.Context) error
+	ChangePassword(c echo.Context) error
+	DeleteAccount(c echo.Context) error
 }
 
 type userController struct {
-	uu usecase.IUserUsecase
+	uu           usecase.IUserUsecase
+	cookieDomain string
 }
 
-func NewUserController(uu usecase.IUserUsecase) IUserController {
-	return &userController{uu}
+func NewUserController(uu usecase.IUserUsecase, cookieDomain string) IUserController {
+	return &userController{uu: uu, cookieDomain: cookieDomain}
 }
 
 func (uc *userController) SignUp(c echo.Context) error {
 	user := model.User{}
 	if err := c.Bind(&user); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
 	userRes, err := uc.uu.SignUp(&user)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, userRes)
@@ -42,37 +50,50 @@ func (uc *userController) SignUp(c echo.Context) error {
 func (uc *userController) Login(c echo.Context) error {
 	user := model.User{}
 	if err := c.Bind(&user); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	tokenString, err := uc.uu.Login(&user)
+	result, err := uc.uu.Login(&user, sessionClient(c))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, err.Error())
+		return err
 	}
-	cookie := new(http.Cookie)
-	cookie.Name = "token"
-	cookie.Value = tokenString
-	cookie.Expires = time.Now().Add(time.Hour * 24)
-	cookie.Path = "/"
-	cookie.Domain = os.Getenv("API_DOMAIN")
-	cookie.Secure = true
-	cookie.HttpOnly = true
-	cookie.SameSite = http.SameSiteNoneMode
-	c.SetCookie(cookie)
+	if result.ChallengeToken != "" {
+		// 2段階認証が有効な場合は /login/2fa で認証コードを受け取るまで Cookie を発行しない
+		return c.JSON(http.StatusOK, model.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.ChallengeToken,
+		})
+	}
+	setAuthCookies(c, uc.cookieDomain, result.Tokens)
+	return c.NoContent(http.StatusOK)
+}
+
+func (uc *userController) RefreshToken(c echo.Context) error {
+	cookie, err := c.Cookie(refreshTokenCookie)
+	if err != nil {
+		return apperror.Unauthorized("missing refresh token")
+	}
+
+	tokens, err := uc.uu.RefreshToken(cookie.Value, sessionClient(c))
+	if err != nil {
+		if apperror.KindOf(err) == apperror.KindUnauthorized {
+			clearAuthCookies(c, uc.cookieDomain)
+		}
+		return err
+	}
+	setAuthCookies(c, uc.cookieDomain, tokens)
 	return c.NoContent(http.StatusOK)
 }
 
 func (uc *userController) LogOut(c echo.Context) error {
-	cookie := new(http.Cookie)
-	cookie.Name = "token"
-	cookie.Value = ""
-	cookie.Expires = time.Now()
-	cookie.Path = "/"
-	cookie.Domain = os.Getenv("API_DOMAIN")
-	cookie.Secure = true
-	cookie.HttpOnly = true
-	cookie.SameSite = http.SameSiteNoneMode
-	c.SetCookie(cookie)
+	refreshToken := ""
+	if cookie, err := c.Cookie(refreshTokenCookie); err == nil {
+		refreshToken = cookie.Value
+	}
+	if err := uc.uu.LogOut(refreshToken); err != nil {
+		return err
+	}
+	clearAuthCookies(c, uc.cookieDomain)
 	return c.NoContent(http.StatusOK)
 }
 
@@ -82,3 +103,114 @@ func (uc *userController) CsrfToken(c echo.Context) error {
 		"csrf_token": token,
 	})
 }
+
+func (uc *userController) GetMe(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	userRes, err := uc.uu.GetMe(uint(userId.(float64)))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, userRes)
+}
+
+func (uc *userController) UpdateProfile(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	request := model.UpdateProfileRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	userRes, err := uc.uu.UpdateProfile(request, uint(userId.(float64)))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, userRes)
+}
+
+func (uc *userController) ChangeEmail(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	request := model.ChangeEmailRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	userRes, err := uc.uu.ChangeEmail(request, uint(userId.(float64)))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, userRes)
+}
+
+func (uc *userController) ChangePassword(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+	sessionId := claims["sid"]
+
+	request := model.ChangePasswordRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	if err := uc.uu.ChangePassword(request, uint(userId.(float64)), uint(sessionId.(float64))); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (uc *userController) DeleteAccount(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["user_id"]
+
+	request := model.DeleteAccountRequest{}
+	if err := c.Bind(&request); err != nil {
+		return err
+	}
+	if err := uc.uu.DeleteAccount(request, uint(userId.(float64))); err != nil {
+		return err
+	}
+	clearAuthCookies(c, uc.cookieDomain)
+	return c.NoContent(http.StatusNoContent)
+}
+
+const (
+	accessTokenCookie  = "token"
+	refreshTokenCookie = "refresh_token"
+)
+
+func sessionClient(c echo.Context) model.SessionClient {
+	return model.SessionClient{
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	}
+}
+
+func setAuthCookies(c echo.Context, domain string, tokens model.AuthTokens) {
+	c.SetCookie(authCookie(domain, accessTokenCookie, tokens.AccessToken, tokens.AccessTokenExpiresAt))
+	c.SetCookie(authCookie(domain, refreshTokenCookie, tokens.RefreshToken, tokens.RefreshTokenExpiresAt))
+}
+
+func clearAuthCookies(c echo.Context, domain string) {
+	c.SetCookie(authCookie(domain, accessTokenCookie, "", time.Now()))
+	c.SetCookie(authCookie(domain, refreshTokenCookie, "", time.Now()))
+}
+
+func authCookie(domain string, name string, value string, expires time.Time) *http.Cookie {
+	cookie := new(http.Cookie)
+	cookie.Name = name
+	cookie.Value = value
+	cookie.Expires = expires
+	cookie.Path = "/"
+	cookie.Domain = domain
+	cookie.Secure = true
+	cookie.HttpOnly = true
+	cookie.SameSite = http.SameSiteNoneMode
+	return cookie
+}
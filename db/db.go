@@ -1,41 +1,30 @@
 package db
 
 import (
-	"fmt"
+	"expiry_tracker/config"
 	"log"
-	"os"
 
-	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func NewDB() *gorm.DB {
-	if os.Getenv("GO_ENV") == "dev" {
-		err := godotenv.Load()
-		if err != nil {
-			log.Printf("Warning: .env file not found: %v", err)
-		}
-	}
-	url := fmt.Sprintf(
-		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		os.Getenv("POSTGRES_USER"),
-		os.Getenv("POSTGRES_PW"),
-		os.Getenv("POSTGRES_HOST"),
-		os.Getenv("POSTGRES_PORT"),
-		os.Getenv("POSTGRES_DB"),
-	)
-	
-	db, err := gorm.Open(postgres.Open(url), &gorm.Config{})
+func NewDB(cfg config.Database) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{
+		// 一意制約違反などを gorm.ErrDuplicatedKey に変換する
+		TranslateError: true,
+	})
 	if err != nil {
 		log.Fatalln(err)
 	}
 	return db
 }
 
-func CloseDB(db *gorm.DB) {
-	sqlDB, _ := db.DB()
-	if err := sqlDB.Close(); err != nil {
-		log.Fatalln(err)
+// CloseDB closes the connection pool, waiting for queries that have started
+// to finish.
+func CloseDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
 	}
-}
\ No newline at end of file
+	return sqlDB.Close()
+}
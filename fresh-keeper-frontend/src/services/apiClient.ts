@@ -9,6 +9,7 @@ import type { ApiConfig, ApiError, CsrfTokenResponse } from '@/types/api';
 class ApiClient {
   private client: AxiosInstance;
   private csrfToken: string | null = null;
+  private refreshing: Promise<void> | null = null;
 
   constructor(config: ApiConfig) {
     this.client = axios.create({
@@ -41,15 +42,36 @@ class ApiClient {
       }
     );
 
-    // レスポンスインターセプター（エラーハンドリング）
+    // レスポンスインターセプター（アクセストークンの再発行とエラーハンドリング）
     this.client.interceptors.response.use(
       (response) => response,
-      (error) => {
+      async (error) => {
+        const config = error.config;
+        if (error.response?.status === 401 && config && !config._retried && !this.isAuthRequest(config.url)) {
+          config._retried = true;
+          try {
+            // 同時に失敗したリクエストでは再発行を 1 回にまとめる
+            this.refreshing ??= this.client.post('/refresh').then(() => undefined).finally(() => {
+              this.refreshing = null;
+            });
+            await this.refreshing;
+            return this.client(config);
+          } catch {
+            return Promise.reject(this.handleError(error));
+          }
+        }
         return Promise.reject(this.handleError(error));
       }
     );
   }
 
+  /**
+   * トークンの再発行を試みないリクエストか
+   */
+  private isAuthRequest(url?: string): boolean {
+    return ['/login', '/login/2fa', '/refresh', '/logout'].some((path) => url?.endsWith(path));
+  }
+
   /**
    * エラーレスポンスを統一形式に変換
    */
@@ -74,6 +74,8 @@ export class AuthService {
       // エラーメッセージを適切に変換
       if (error.status === 401) {
         throw new Error('メールアドレスまたはパスワードが正しくありません');
+      } else if (error.status === 429) {
+        throw new Error('ログインの試行回数が多すぎます。しばらくしてから再度お試しください');
       } else if (error.status === 400) {
         throw new Error('入力内容に不備があります');
       } else if (error.status === 0) {
@@ -106,13 +108,7 @@ export class AuthService {
    */
   async getCurrentUser(): Promise<UserResponse | null> {
     try {
-      // この実装はバックエンドに /me エンドポイントがある場合
-      // 現在のバックエンドにはないので、ログイン状態の確認は別の方法で行う
-      
-      // バックエンドに専用のエンドポイントがないため、
-      // ログイン時に保存したユーザー情報を使用する
-      // 実際の認証状態確認は必要に応じて製品一覧などのAPIで行う
-      return null;
+      return await apiClient.get<UserResponse>(API_ENDPOINTS.USER.ME);
     } catch (error) {
       console.error('Get current user error:', error);
       return null;
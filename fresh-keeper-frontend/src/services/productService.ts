@@ -6,6 +6,7 @@ import { apiClient } from './apiClient';
 import type { ProductResponse, ProductCreateData, ProductUpdateData } from '@/types/models';
 import type { 
   ProductListResponse,
+  ProductPage,
   ProductDetailResponse,
   ProductCreateResponse,
   ProductUpdateResponse,
@@ -19,12 +20,13 @@ export class ProductService {
    */
   async getProducts(): Promise<ProductResponse[]> {
     try {
-      const response = await apiClient.get<ProductResponse[]>(
-        API_ENDPOINTS.PRODUCTS.LIST
+      const response = await apiClient.get<ProductPage>(
+        API_ENDPOINTS.PRODUCTS.LIST,
+        { params: { per_page: 100 } }
       );
       
-      // バックエンドは直接配列を返すため、そのまま使用
-      return Array.isArray(response) ? response : [];
+      // バックエンドはページ情報付きのオブジェクトを返す
+      return Array.isArray(response?.items) ? response.items : [];
     } catch (error) {
       console.error('Get products error:', error);
       throw new Error('製品一覧の取得に失敗しました');
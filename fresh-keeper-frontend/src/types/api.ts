@@ -3,7 +3,7 @@
  * HTTPリクエスト・レスポンス、エラーハンドリング関連
  */
 
-import type { UserResponse, ProductResponse } from './models';
+import type { UserResponse, ProductResponse, ExpiryType } from './models';
 
 // ===== 共通API型 =====
 
@@ -16,13 +16,22 @@ export interface ApiResponse<T = any> {
   success: boolean;
 }
 
+/**
+ * フィールド単位のバリデーションエラー
+ * code は機械判定用（例: validation_required）、message は表示用
+ */
+export interface FieldError {
+  code: string;
+  message: string;
+}
+
 /**
  * APIエラーレスポンス
  */
 export interface ApiError {
   message: string;
   status: number;
-  errors?: string[]; // バリデーションエラーの詳細
+  errors?: Record<string, FieldError>; // JSONフィールド名ごとのバリデーションエラー
 }
 
 /**
@@ -65,6 +74,17 @@ export interface CsrfTokenResponse extends ApiResponse {
  */
 export interface ProductListResponse extends ApiResponse<ProductResponse[]> {}
 
+/**
+ * GET /products のレスポンス（ページネーション付き）
+ */
+export interface ProductPage {
+  items: ProductResponse[];
+  total: number;
+  page: number;
+  per_page: number;
+  total_pages: number;
+}
+
 /**
  * 製品詳細APIレスポンス
  */
@@ -85,6 +105,35 @@ export interface ProductUpdateResponse extends ApiResponse<ProductResponse> {}
  */
 export interface ProductDeleteResponse extends ApiResponse {}
 
+// ===== 統計API型 =====
+
+export interface ExpiredStatsResponse {
+  period: 'week' | 'month';
+  buckets: { period_start: string; products: number; quantity: number }[];
+}
+
+export interface WasteStats {
+  consumed: number;
+  wasted: number;
+  waste_rate: number; // 0〜1
+}
+
+export interface WasteStatsResponse {
+  total: WasteStats;
+  by_type: (WasteStats & { type: ExpiryType })[];
+}
+
+export interface ConsumptionTimeStats {
+  average_days: number;
+  consumptions: number;
+}
+
+export interface UpcomingExpiryStats {
+  expired: number;
+  within_7_days: number;
+  within_30_days: number;
+}
+
 // ===== API設定型 =====
 
 /**
@@ -104,8 +153,40 @@ export const API_ENDPOINTS = {
   AUTH: {
     SIGNUP: '/signup',
     LOGIN: '/login',
+    LOGIN_TWO_FACTOR: '/login/2fa',
+    REFRESH: '/refresh',
     LOGOUT: '/logout',
     CSRF: '/csrf',
+    FORGOT_PASSWORD: '/password/forgot',
+    RESET_PASSWORD: '/password/reset',
+    VERIFY_EMAIL: '/email/verify',
+    RESEND_VERIFICATION: '/email/verify/resend',
+    OIDC_LOGIN: '/oidc/login', // ブラウザで遷移する（XHR では呼ばない）
+  },
+  // アカウント
+  USER: {
+    ME: '/me',
+    UPDATE: '/me',
+    EMAIL: '/me/email',
+    PASSWORD: '/me/password',
+    DELETE: '/me',
+  },
+  // 二要素認証
+  TWO_FACTOR: {
+    ENROLL: '/2fa/enroll',
+    CONFIRM: '/2fa/confirm',
+    DISABLE: '/2fa/disable',
+  },
+  // API トークン
+  API_TOKENS: {
+    LIST: '/tokens',
+    CREATE: '/tokens',
+    DELETE: (id: number) => `/tokens/${id}`,
+  },
+  // セッション
+  SESSIONS: {
+    LIST: '/sessions',
+    DELETE: (id: number) => `/sessions/${id}`,
   },
   // 製品
   PRODUCTS: {
@@ -114,9 +195,42 @@ export const API_ENDPOINTS = {
     DETAIL: (id: number) => `/products/${id}`,
     UPDATE: (id: number) => `/products/${id}`,
     DELETE: (id: number) => `/products/${id}`,
+    CONSUME: (id: number) => `/products/${id}/consume`,
+    DISCARD: (id: number) => `/products/${id}/discard`,
+    EVENTS: (id: number) => `/products/${id}/events`,
+    OPEN: (id: number) => `/products/${id}/open`,
+    FREEZE: (id: number) => `/products/${id}/freeze`,
+    THAW: (id: number) => `/products/${id}/thaw`,
+  },
+  // 統計
+  STATS: {
+    EXPIRED: '/stats/expired',
+    WASTE: '/stats/waste',
+    CONSUMPTION_TIME: '/stats/consumption-time',
+    UPCOMING: '/stats/upcoming',
+  },
+  // 世帯
+  HOUSEHOLDS: {
+    LIST: '/households',
+    CREATE: '/households',
+    DETAIL: (id: number) => `/households/${id}`,
+    UPDATE: (id: number) => `/households/${id}`,
+    DELETE: (id: number) => `/households/${id}`,
+    MEMBER: (id: number, userId: number) => `/households/${id}/members/${userId}`,
+    INVITATIONS: (id: number) => `/households/${id}/invitations`,
+    INVITATION: (id: number, invitationId: number) => `/households/${id}/invitations/${invitationId}`,
+  },
+  INVITATIONS: {
+    ACCEPT: '/invitations/accept',
+    DECLINE: '/invitations/decline',
   },
 } as const;
 
+/**
+ * 対象の世帯を指定するリクエストヘッダー（省略時は最初に所属した世帯）
+ */
+export const HOUSEHOLD_HEADER = 'X-Household-Id';
+
 // ===== HTTPメソッド型 =====
 
 /**
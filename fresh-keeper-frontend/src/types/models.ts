@@ -12,6 +12,12 @@
  */
 export type ExpiryType = 'best_before' | 'use_by';
 
+/**
+ * サーバー側で計算される期限の状態
+ * 消費期限と賞味期限で「期限間近」の閾値が異なる
+ */
+export type ExpiryStatus = 'fresh' | 'expiring_soon' | 'expired';
+
 // ===== ユーザー関連型 =====
 
 /**
@@ -33,6 +39,9 @@ export interface UserResponse {
   id: number;
   email: string;
   name: string;
+  time_zone?: string; // IANAタイムゾーン名（例: Asia/Tokyo）
+  email_verified_at?: string | null; // 未確認の場合は null
+  two_factor_enabled?: boolean;
 }
 
 /**
@@ -52,6 +61,138 @@ export interface UserLoginData {
   password: string;
 }
 
+/**
+ * プロフィールの更新
+ */
+export interface UpdateProfileData {
+  name: string;
+  time_zone?: string;
+}
+
+/**
+ * メールアドレスの変更（変更後のアドレスは確認が済むまで未確認になる）
+ */
+export interface ChangeEmailData {
+  email: string;
+  current_password: string;
+}
+
+/**
+ * パスワードの変更（他の端末はログアウトされる）
+ */
+export interface ChangePasswordData {
+  current_password: string;
+  new_password: string;
+}
+
+/**
+ * アカウントの削除
+ */
+export interface DeleteAccountData {
+  current_password: string;
+}
+
+/**
+ * パスワード再設定メールの送信依頼
+ */
+export interface ForgotPasswordData {
+  email: string;
+}
+
+/**
+ * パスワード再設定（メールのリンクに含まれるトークンを使う）
+ */
+export interface ResetPasswordData {
+  token: string;
+  password: string;
+}
+
+/**
+ * メールアドレスの確認（確認メールのリンクに含まれるトークンを使う）
+ */
+export interface VerifyEmailData {
+  token: string;
+}
+
+/**
+ * 二要素認証が有効な場合の POST /login のレスポンス
+ */
+export interface TwoFactorChallengeResponse {
+  two_factor_required: true;
+  challenge_token: string; // 5分間有効
+}
+
+/**
+ * POST /login/2fa のリクエスト
+ */
+export interface TwoFactorLoginRequest {
+  challenge_token: string;
+  code: string; // 認証アプリのコードまたはリカバリーコード
+}
+
+/**
+ * POST /2fa/enroll のレスポンス
+ */
+export interface TwoFactorEnrollResponse {
+  secret: string;
+  otpauth_uri: string; // QRコードにして認証アプリで読み取る
+}
+
+/**
+ * POST /2fa/confirm のレスポンス
+ */
+export interface RecoveryCodesResponse {
+  recovery_codes: string[]; // この応答でしか取得できない
+}
+
+/**
+ * POST /2fa/disable のリクエスト
+ */
+export interface TwoFactorDisableRequest {
+  current_password: string;
+  code: string;
+}
+
+/**
+ * API トークンのスコープ
+ */
+export type ApiTokenScope = 'read' | 'write';
+
+/**
+ * POST /tokens のリクエスト
+ */
+export interface ApiTokenRequest {
+  name: string;
+  scope: ApiTokenScope;
+  expires_in_days?: number; // 省略時は無期限
+}
+
+/**
+ * API トークン
+ */
+export interface ApiTokenResponse {
+  id: number;
+  name: string;
+  scope: ApiTokenScope;
+  expires_at: string | null;
+  last_used_at: string | null;
+  created_at: string;
+  token?: string; // 作成時のみ
+}
+
+/**
+ * ログイン中のセッション（端末）
+ */
+export interface SessionResponse {
+  id: number;
+  user_agent: string;
+  ip_address: string;
+  current: boolean; // このリクエストのセッション
+  last_used_at: string;
+  expires_at: string;
+  created_at: string;
+}
+
 // ===== 製品（食材）関連型 =====
 
 /**
@@ -77,13 +218,24 @@ export interface Product {
  */
 export interface ProductResponse {
   id: number;
+  household_id: number;
   name: string;
   description: string;
   quantity: number;
   expiry_date: string;
   type: ExpiryType;
   is_notified: boolean;
-  days_left: number; // 賞味期限までの残り日数
+  days_left: number; // 賞味期限までの残り日数（ユーザーのタイムゾーンでの暦日）
+  status: ExpiryStatus;
+  archived_at: string | null; // 数量が0になりアーカイブされた日時
+  opened_at: string | null; // 開封日時
+  days_after_opening: number | null; // 開封後に日持ちする日数
+  frozen_at: string | null; // 冷凍日時
+  thawed_at: string | null; // 解凍日時
+  freezer_shelf_life_days: number | null; // 冷凍した時点で決まる冷凍保存の日数
+  effective_expiry_date: string; // 冷凍・解凍・開封を考慮した実際の期限
+  category: CategoryResponse | null;
+  storage_location: StorageLocationResponse | null;
   created_at: string;
   updated_at: string;
 }
@@ -97,6 +249,69 @@ export interface ProductCreateData {
   quantity: number;
   expiry_date: string; // YYYY-MM-DD形式
   type: ExpiryType;
+  category_id?: number | null;
+  storage_location_id?: number | null;
+  days_after_opening?: number | null;
+}
+
+// ===== 世帯 =====
+
+export type HouseholdRole = 'owner' | 'editor' | 'viewer';
+
+export interface HouseholdMemberResponse {
+  user_id: number;
+  name: string;
+  email: string;
+  role: HouseholdRole;
+  joined_at: string;
+}
+
+export interface HouseholdResponse {
+  id: number;
+  name: string;
+  role: HouseholdRole; // 自分の役割
+  members?: HouseholdMemberResponse[]; // 詳細取得時のみ
+  created_at: string;
+  updated_at: string;
+}
+
+export interface HouseholdInvitationRequest {
+  email?: string; // 指定するとそのアドレスのユーザーだけが参加できる
+  role: HouseholdRole;
+  expires_in_days?: number; // 省略時は 7 日
+}
+
+export interface HouseholdInvitationResponse {
+  id: number;
+  household_id: number;
+  household_name: string;
+  inviter_name: string;
+  email: string;
+  role: HouseholdRole;
+  expires_at: string;
+  created_at: string;
+  token?: string; // 発行時のみ
+  url?: string; // 発行時のみ
+}
+
+// ===== カテゴリ・保存場所 =====
+
+export interface CategoryResponse {
+  id: number;
+  name: string;
+  freezer_shelf_life_days: number | null;
+  created_at: string;
+  updated_at: string;
+}
+
+export type StorageLocationKind = 'fridge' | 'freezer' | 'pantry' | 'other';
+
+export interface StorageLocationResponse {
+  id: number;
+  name: string;
+  kind: StorageLocationKind;
+  created_at: string;
+  updated_at: string;
 }
 
 /**
@@ -109,6 +324,22 @@ export interface ProductUpdateData {
   expiry_date: string; // YYYY-MM-DDTHH:mm:ss.sssZ形式
   type: ExpiryType;
   is_notified?: boolean;
+  category_id?: number | null;
+  storage_location_id?: number | null;
+  days_after_opening?: number | null;
+}
+
+/**
+ * 消費・廃棄の履歴
+ */
+export type ConsumptionEventKind = 'consumed' | 'wasted';
+
+export interface ConsumptionEventResponse {
+  id: number;
+  product_id: number;
+  kind: ConsumptionEventKind;
+  amount: number;
+  occurred_at: string;
 }
 
 // ===== ヘルパー型 =====
@@ -0,0 +1,159 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// IManager runs the servers and background workers of the process and shuts
+// them down in order when the process is asked to stop.
+type IManager interface {
+	// Serve runs start, which blocks while the server is running. stop must
+	// make start return, finishing in-flight requests before ctx is done.
+	Serve(name string, start func() error, stop func(ctx context.Context) error)
+	// Go runs a background worker, which must return once ctx is cancelled.
+	Go(name string, run func(ctx context.Context))
+	// OnStop registers a cleanup that runs after the servers and workers have
+	// stopped, in reverse order of registration.
+	OnStop(name string, stop func(ctx context.Context) error)
+	// Run starts everything and blocks until SIGINT or SIGTERM is received,
+	// ctx is cancelled or a server fails, then shuts down.
+	Run(ctx context.Context) error
+}
+
+type server struct {
+	name  string
+	start func() error
+	stop  func(ctx context.Context) error
+}
+
+type worker struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+type manager struct {
+	shutdownTimeout time.Duration
+	servers         []server
+	workers         []worker
+	hooks           []hook
+}
+
+// NewManager returns a manager that gives shutdown at most shutdownTimeout
+// before it gives up waiting and runs the cleanups.
+func NewManager(shutdownTimeout time.Duration) IManager {
+	return &manager{shutdownTimeout: shutdownTimeout}
+}
+
+func (m *manager) Serve(name string, start func() error, stop func(ctx context.Context) error) {
+	m.servers = append(m.servers, server{name: name, start: start, stop: stop})
+}
+
+func (m *manager) Go(name string, run func(ctx context.Context)) {
+	m.workers = append(m.workers, worker{name: name, run: run})
+}
+
+func (m *manager) OnStop(name string, stop func(ctx context.Context) error) {
+	m.hooks = append(m.hooks, hook{name: name, stop: stop})
+}
+
+func (m *manager) Run(ctx context.Context) error {
+	ctx, stopSignals := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	defer cancelWorkers()
+	var mu sync.Mutex
+	running := map[string]int{}
+	var workers sync.WaitGroup
+	for _, w := range m.workers {
+		running[w.name]++
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			w.run(workerCtx)
+			mu.Lock()
+			running[w.name]--
+			mu.Unlock()
+		}()
+	}
+
+	serverErrs := make(chan error, len(m.servers))
+	for _, s := range m.servers {
+		go func() {
+			err := s.start()
+			if errors.Is(err, http.ErrServerClosed) {
+				err = nil
+			}
+			if err != nil {
+				err = fmt.Errorf("%s: %w", s.name, err)
+			}
+			serverErrs <- err
+		}()
+	}
+
+	var errs []error
+	select {
+	case <-ctx.Done():
+		log.Println("shutting down")
+	case err := <-serverErrs:
+		// サーバーが停止した場合も残りを止めて終了する
+		if err != nil {
+			errs = append(errs, err)
+		}
+		log.Println("server stopped, shutting down")
+	}
+	// 2 回目のシグナルではすぐに終了できるようにする
+	stopSignals()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	// 処理中のリクエストを終えてからワーカーを止め、最後にデータベースなどを閉じる
+	for _, s := range m.servers {
+		if err := s.stop(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", s.name, err))
+		}
+	}
+	cancelWorkers()
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		mu.Lock()
+		var names []string
+		for name, n := range running {
+			if n > 0 {
+				names = append(names, name)
+			}
+		}
+		mu.Unlock()
+		sort.Strings(names)
+		errs = append(errs, fmt.Errorf("timed out waiting for %s", strings.Join(names, ", ")))
+	}
+	for i := len(m.hooks) - 1; i >= 0; i-- {
+		if err := m.hooks[i].stop(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", m.hooks[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
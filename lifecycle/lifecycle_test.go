@@ -0,0 +1,125 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder は停止処理が呼ばれた順番を記録する
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.events, ",")
+}
+
+// fakeServer は stop が呼ばれるまで start がブロックするサーバー
+func fakeServer(r *recorder, name string) (func() error, func(ctx context.Context) error) {
+	stopped := make(chan struct{})
+	start := func() error {
+		<-stopped
+		return http.ErrServerClosed
+	}
+	stop := func(ctx context.Context) error {
+		r.add("stop " + name)
+		close(stopped)
+		return nil
+	}
+	return start, stop
+}
+
+func TestManager_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(m IManager, r *recorder, cancel context.CancelFunc)
+		wantErr    string
+		wantEvents string
+	}{
+		{
+			name: "キャンセルでサーバー、ワーカー、後処理の順に停止する",
+			setup: func(m IManager, r *recorder, cancel context.CancelFunc) {
+				m.OnStop("database", func(ctx context.Context) error { r.add("close database"); return nil })
+				m.OnStop("mailer", func(ctx context.Context) error { r.add("close mailer"); return nil })
+				m.Go("worker", func(ctx context.Context) {
+					<-ctx.Done()
+					r.add("stop worker")
+				})
+				start, stop := fakeServer(r, "server")
+				m.Serve("server", start, stop)
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					cancel()
+				}()
+			},
+			wantEvents: "stop server,stop worker,close mailer,close database",
+		},
+		{
+			name: "サーバーが失敗すると残りを停止してエラーを返す",
+			setup: func(m IManager, r *recorder, cancel context.CancelFunc) {
+				m.OnStop("database", func(ctx context.Context) error { r.add("close database"); return nil })
+				m.Serve("broken", func() error { return errors.New("address already in use") }, func(ctx context.Context) error {
+					r.add("stop broken")
+					return nil
+				})
+				start, stop := fakeServer(r, "server")
+				m.Serve("server", start, stop)
+			},
+			wantErr:    "broken: address already in use",
+			wantEvents: "stop broken,stop server,close database",
+		},
+		{
+			name: "止まらないワーカーはタイムアウトし、後処理は実行する",
+			setup: func(m IManager, r *recorder, cancel context.CancelFunc) {
+				m.OnStop("database", func(ctx context.Context) error { r.add("close database"); return nil })
+				m.Go("stuck", func(ctx context.Context) { select {} })
+				cancel()
+			},
+			wantErr:    "timed out waiting for stuck",
+			wantEvents: "close database",
+		},
+		{
+			name: "後処理のエラーを返す",
+			setup: func(m IManager, r *recorder, cancel context.CancelFunc) {
+				m.OnStop("database", func(ctx context.Context) error { return errors.New("connection busy") })
+				cancel()
+			},
+			wantErr: "stop database: connection busy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(100 * time.Millisecond)
+			r := &recorder{}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			tt.setup(m, r, cancel)
+
+			err := m.Run(ctx)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Run() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Run() error = %v, want %q", err, tt.wantErr)
+			}
+			if got := r.String(); got != tt.wantEvents {
+				t.Errorf("events = %q, want %q", got, tt.wantEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type fileMailer struct {
+	dir  string
+	from string
+	mu   sync.Mutex
+	seq  int
+}
+
+// NewFileMailer returns a mailer that writes each message as an .eml file
+// under dir, for local development where links in mails need to be opened.
+func NewFileMailer(dir string, from string) IMailer {
+	return &fileMailer{dir: dir, from: from}
+}
+
+func (fm *fileMailer) Send(to string, subject string, body string) error {
+	now := time.Now()
+	msg, err := buildMessage(fm.from, to, subject, body, now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(fm.dir, 0o755); err != nil {
+		return err
+	}
+
+	fm.mu.Lock()
+	fm.seq++
+	name := fmt.Sprintf("%s-%d.eml", now.Format("20060102T150405.000"), fm.seq)
+	fm.mu.Unlock()
+	return os.WriteFile(filepath.Join(fm.dir, name), msg, 0o600)
+}
@@ -0,0 +1,58 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"strings"
+	"time"
+)
+
+type IMailer interface {
+	Send(to string, subject string, body string) error
+}
+
+type logMailer struct{}
+
+// NewLogMailer returns a mailer that only writes messages to the log, for
+// development environments without a mail server.
+func NewLogMailer() IMailer {
+	return &logMailer{}
+}
+
+func (lm *logMailer) Send(to string, subject string, body string) error {
+	log.Printf("mail to=%s subject=%q\n%s", to, subject, body)
+	return nil
+}
+
+// buildMessage renders a plain text UTF-8 message in RFC 5322 format. The
+// body is base64 encoded so that it passes through servers without 8BITMIME.
+func buildMessage(from string, to string, subject string, body string, date time.Time) ([]byte, error) {
+	// ヘッダーインジェクションを防ぐ
+	for _, v := range []string{from, to, subject} {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, errors.New("mailer: header must not contain line breaks")
+		}
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: base64\r\n")
+	msg.WriteString("\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	for len(encoded) > 76 {
+		msg.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	msg.WriteString(encoded + "\r\n")
+	return msg.Bytes(), nil
+}
@@ -0,0 +1,111 @@
+package mailer
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildMessage(t *testing.T) {
+	body := strings.Repeat("パスワードの再設定はこちら。", 10)
+	msg, err := buildMessage("noreply@example.com", "user@example.com", "パスワードの再設定", body, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("buildMessage() error = %v", err)
+	}
+
+	header, encoded, found := strings.Cut(string(msg), "\r\n\r\n")
+	if !found {
+		t.Fatal("message has no header/body separator")
+	}
+	for _, want := range []string{
+		"From: noreply@example.com",
+		"To: user@example.com",
+		"Subject: =?UTF-8?b?",
+		"Date: Tue, 02 Jan 2024 03:04:05 +0000",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding: base64",
+	} {
+		if !strings.Contains(header, want) {
+			t.Errorf("header does not contain %q:\n%s", want, header)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(encoded, "\r\n"), "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("body line is %d chars, want at most 76", len(line))
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(encoded, "\r\n", ""))
+	if err != nil {
+		t.Fatalf("body is not valid base64: %v", err)
+	}
+	if string(decoded) != body {
+		t.Errorf("decoded body = %q, want %q", decoded, body)
+	}
+}
+
+func TestBuildMessage_HeaderInjection(t *testing.T) {
+	tests := []struct {
+		name    string
+		to      string
+		subject string
+	}{
+		{name: "宛先に改行", to: "user@example.com\r\nBcc: other@example.com", subject: "件名"},
+		{name: "件名に改行", to: "user@example.com", subject: "件名\nBcc: other@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildMessage("noreply@example.com", tt.to, tt.subject, "本文", time.Now()); err == nil {
+				t.Error("buildMessage() accepted a header with a line break")
+			}
+		})
+	}
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m := NewFileMailer(dir, "noreply@example.com")
+
+	if err := m.Send("user@example.com", "件名", "本文"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := m.Send("user@example.com", "件名", "本文"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("wrote %d files, want 2", len(files))
+	}
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "To: user@example.com") {
+		t.Errorf("file does not contain the recipient:\n%s", content)
+	}
+}
+
+func TestMemoryMailer_Send(t *testing.T) {
+	m := NewMemoryMailer()
+	if err := m.Send("a@example.com", "1", "本文1"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := m.Send("b@example.com", "2", "本文2"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	messages := m.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("len(Messages()) = %d, want 2", len(messages))
+	}
+	if messages[0] != (Message{To: "a@example.com", Subject: "1", Body: "本文1"}) {
+		t.Errorf("Messages()[0] = %+v", messages[0])
+	}
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer returns a mailer that delivers through an SMTP server. The
+// connection is upgraded with STARTTLS when the server offers it; credentials
+// are optional for relays that do not require authentication.
+func NewSMTPMailer(host string, port int, username string, password string, from string) IMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpMailer{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (sm *smtpMailer) Send(to string, subject string, body string) error {
+	msg, err := buildMessage(sm.from, to, subject, body, time.Now())
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(sm.addr, sm.auth, sm.from, []string{to}, msg)
+}
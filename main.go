@@ -1,24 +1,177 @@
 package main
 
 import (
+	"context"
+	"expiry_tracker/config"
 	"expiry_tracker/controller"
 	"expiry_tracker/db"
+	"expiry_tracker/lifecycle"
+	"expiry_tracker/mailer"
+	"expiry_tracker/metrics"
+	"expiry_tracker/migrations"
+	"expiry_tracker/notifier"
 	"expiry_tracker/repository"
 	"expiry_tracker/router"
+	"expiry_tracker/sso"
 	"expiry_tracker/usecase"
 	"expiry_tracker/validator"
+	"expiry_tracker/worker"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
 )
 
 func main() {
-	db := db.NewDB()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+	keys := usecase.NewTokenKeys(cfg.Secret)
+	dbConn := db.NewDB(cfg.Database)
+	appMetrics := metrics.New()
+	if err := dbConn.Use(appMetrics.GormPlugin()); err != nil {
+		log.Fatalln(err)
+	}
+	sqlDB, err := dbConn.DB()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := appMetrics.RegisterDB(sqlDB, cfg.Database.Name); err != nil {
+		log.Fatalln(err)
+	}
 	userValidator := validator.NewUserValidator()
 	productValidator := validator.NewProductValidator()
-	userRepository := repository.NewUserRepository(db)
-	productRepository := repository.NewProductRepository(db)
-	userUsecase := usecase.NewUserUsecase(userRepository, userValidator)
-	productUsecase := usecase.NewProductUsecase(productRepository, productValidator)
-	userController := controller.NewUserController(userUsecase)
+	statsValidator := validator.NewStatsValidator()
+	categoryValidator := validator.NewCategoryValidator()
+	storageLocationValidator := validator.NewStorageLocationValidator()
+	householdValidator := validator.NewHouseholdValidator()
+	householdInvitationValidator := validator.NewHouseholdInvitationValidator()
+	passwordResetValidator := validator.NewPasswordResetValidator()
+	twoFactorValidator := validator.NewTwoFactorValidator()
+	apiTokenValidator := validator.NewApiTokenValidator()
+	userRepository := repository.NewUserRepository(dbConn)
+	productRepository := repository.NewProductRepository(dbConn)
+	expiryNotificationRepository := repository.NewExpiryNotificationRepository(dbConn)
+	statsRepository := repository.NewStatsRepository(dbConn)
+	categoryRepository := repository.NewCategoryRepository(dbConn)
+	storageLocationRepository := repository.NewStorageLocationRepository(dbConn)
+	householdRepository := repository.NewHouseholdRepository(dbConn)
+	householdInvitationRepository := repository.NewHouseholdInvitationRepository(dbConn)
+	sessionRepository := repository.NewSessionRepository(dbConn)
+	passwordResetRepository := repository.NewPasswordResetRepository(dbConn)
+	emailVerificationRepository := repository.NewEmailVerificationRepository(dbConn)
+	twoFactorRepository := repository.NewTwoFactorRepository(dbConn)
+	apiTokenRepository := repository.NewApiTokenRepository(dbConn)
+	userIdentityRepository := repository.NewUserIdentityRepository(dbConn)
+	mailer := newMailer(cfg.Mail)
+	attemptCounterRepository := newAttemptCounterRepository(cfg.RateLimit, dbConn)
+	loginLimiter := usecase.NewAttemptLimiter(attemptCounterRepository, attemptLimit(cfg.RateLimit.Login))
+	authRequestLimiter := usecase.NewAttemptLimiter(attemptCounterRepository, attemptLimit(cfg.RateLimit.Auth))
+	userUsecase := usecase.NewUserUsecase(userRepository, sessionRepository, emailVerificationRepository, mailer, loginLimiter, userValidator, cfg.Server.FEURL, keys)
+	sessionUsecase := usecase.NewSessionUsecase(sessionRepository)
+	passwordResetUsecase := usecase.NewPasswordResetUsecase(passwordResetRepository, userRepository, mailer, passwordResetValidator, cfg.Server.FEURL, keys)
+	emailVerificationUsecase := usecase.NewEmailVerificationUsecase(emailVerificationRepository, userRepository, mailer, userValidator, cfg.Server.FEURL, keys)
+	twoFactorUsecase := usecase.NewTwoFactorUsecase(userRepository, twoFactorRepository, sessionRepository, loginLimiter, twoFactorValidator, cfg.TotpIssuer, keys)
+	apiTokenUsecase := usecase.NewApiTokenUsecase(apiTokenRepository, apiTokenValidator)
+	expiryPolicy := usecase.NewExpiryPolicy(
+		cfg.Expiry.UseByWarningDays,
+		cfg.Expiry.BestBeforeWarningDays,
+		cfg.Expiry.FreezerShelfLifeDays,
+		cfg.Expiry.ThawedShelfLifeDays,
+	)
+	productUsecase := usecase.NewProductUsecase(productRepository, userRepository, categoryRepository, storageLocationRepository, householdRepository, productValidator, expiryPolicy)
+	categoryUsecase := usecase.NewCategoryUsecase(categoryRepository, householdRepository, categoryValidator)
+	storageLocationUsecase := usecase.NewStorageLocationUsecase(storageLocationRepository, householdRepository, storageLocationValidator)
+	householdUsecase := usecase.NewHouseholdUsecase(householdRepository, householdValidator)
+	householdInvitationUsecase := usecase.NewHouseholdInvitationUsecase(householdInvitationRepository, householdRepository, userRepository, mailer, householdInvitationValidator, cfg.Server.FEURL, keys)
+	statsUsecase := usecase.NewStatsUsecase(statsRepository, userRepository, householdRepository, statsValidator)
+	notificationUsecase := usecase.NewNotificationUsecase(productRepository, householdRepository, expiryNotificationRepository, notifier.NewLogNotifier(), expiryPolicy, appMetrics)
+	userController := controller.NewUserController(userUsecase, cfg.Server.APIDomain)
 	productController := controller.NewProductController(productUsecase)
-	e := router.NewRouter(userController, productController)
-	e.Logger.Fatal(e.Start(":8080"))
+	statsController := controller.NewStatsController(statsUsecase)
+	categoryController := controller.NewCategoryController(categoryUsecase)
+	storageLocationController := controller.NewStorageLocationController(storageLocationUsecase)
+	householdController := controller.NewHouseholdController(householdUsecase)
+	householdInvitationController := controller.NewHouseholdInvitationController(householdInvitationUsecase)
+	sessionController := controller.NewSessionController(sessionUsecase)
+	passwordResetController := controller.NewPasswordResetController(passwordResetUsecase, cfg.Server.APIDomain)
+	emailVerificationController := controller.NewEmailVerificationController(emailVerificationUsecase)
+	twoFactorController := controller.NewTwoFactorController(twoFactorUsecase, cfg.Server.APIDomain)
+	apiTokenController := controller.NewApiTokenController(apiTokenUsecase)
+	var oidcController controller.IOidcController
+	if provider := newOidcProvider(cfg.Oidc); provider != nil {
+		oidcUsecase := usecase.NewOidcUsecase(provider, userRepository, userIdentityRepository, sessionRepository, keys)
+		oidcController = controller.NewOidcController(oidcUsecase, cfg.Server.FEURL, cfg.Server.APIDomain)
+	}
+	notificationWorker := worker.NewExpiryNotificationWorker(notificationUsecase, cfg.NotifyInterval)
+	embeddedMigrations, err := migrations.Embedded()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	healthRepository := repository.NewHealthRepository(dbConn, embeddedMigrations)
+	healthUsecase := usecase.NewHealthUsecase(healthRepository, map[string]usecase.IHeartbeat{
+		"expiry notification worker": notificationWorker,
+	})
+	healthController := controller.NewHealthController(healthUsecase)
+	e := router.NewRouter(cfg, userController, productController, statsController, categoryController, storageLocationController, householdController, householdInvitationController, sessionController, passwordResetController, emailVerificationController, twoFactorController, apiTokenController, oidcController, healthController,
+		controller.RateLimitByIP(authRequestLimiter, "auth"), appMetrics)
+
+	lc := lifecycle.NewManager(cfg.Server.ShutdownTimeout)
+	lc.OnStop("database", func(ctx context.Context) error { return db.CloseDB(dbConn) })
+	lc.Go("expiry notification worker", notificationWorker.Run)
+	lc.Serve("http server", func() error { return e.Start(fmt.Sprintf(":%d", cfg.Server.Port)) }, e.Shutdown)
+	if err := lc.Run(context.Background()); err != nil {
+		log.Fatalln(err)
+	}
+	log.Println("stopped")
+}
+
+// newMailer selects the mailer configured with MAILER.
+func newMailer(cfg config.Mail) mailer.IMailer {
+	switch cfg.Mailer {
+	case "file":
+		return mailer.NewFileMailer(cfg.Dir, cfg.From)
+	case "smtp":
+		return mailer.NewSMTPMailer(cfg.Smtp.Host, cfg.Smtp.Port, cfg.Smtp.Username, cfg.Smtp.Password, cfg.From)
+	}
+	return mailer.NewLogMailer()
+}
+
+// newOidcProvider configures login with an OpenID Connect identity provider.
+// It returns nil when OIDC_ISSUER is not set.
+func newOidcProvider(cfg config.Oidc) sso.IProvider {
+	if cfg.Issuer == "" {
+		return nil
+	}
+	return sso.NewProvider(sso.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       cfg.Scopes,
+	})
+}
+
+// newAttemptCounterRepository selects where attempt counters are kept with
+// RATE_LIMIT_STORE.
+func newAttemptCounterRepository(cfg config.RateLimit, db *gorm.DB) repository.IAttemptCounterRepository {
+	if cfg.Store == "postgres" {
+		// 遅延も期間も過ぎたカウンターは試行に影響しないため削除する
+		retention := max(cfg.Login.Window, cfg.Login.MaxDelay, cfg.Auth.Window, cfg.Auth.MaxDelay)
+		return repository.NewAttemptCounterRepository(db, retention)
+	}
+	return repository.NewMemoryAttemptCounterRepository()
+}
+
+func attemptLimit(cfg config.AttemptLimit) usecase.AttemptLimit {
+	return usecase.AttemptLimit{
+		FreeAttempts: cfg.FreeAttempts,
+		BaseDelay:    cfg.BaseDelay,
+		MaxDelay:     cfg.MaxDelay,
+		Window:       cfg.Window,
+	}
 }
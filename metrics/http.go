@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware records every request under its route pattern, such as
+// /products/:productId, so that IDs in paths do not create new series.
+func (m *Metrics) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				// エラーハンドラーでステータスコードを確定させてから記録する
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			labels := []string{c.Request().Method, route, strconv.Itoa(c.Response().Status)}
+			m.httpRequests.WithLabelValues(labels...).Inc()
+			m.httpDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "expiry_tracker"
+
+// Notification results counted by expiry_tracker_notifications_total.
+const (
+	resultQueued = "queued"
+	resultSent   = "sent"
+	resultFailed = "failed"
+)
+
+// Metrics holds the application's Prometheus collectors in a registry of its
+// own, so that tests can create as many as they need.
+type Metrics struct {
+	registry         *prometheus.Registry
+	httpRequests     *prometheus.CounterVec
+	httpDuration     *prometheus.HistogramVec
+	dbQueryDuration  *prometheus.HistogramVec
+	dbQueryErrors    *prometheus.CounterVec
+	notifications    *prometheus.CounterVec
+	expiringProducts prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Database query latency by operation and table.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+		dbQueryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_query_errors_total",
+			Help:      "Failed database queries by operation and table, not counting record not found.",
+		}, []string{"operation", "table"}),
+		notifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifications_total",
+			Help:      "Expiry notifications by result: queued for a recipient, sent, or failed.",
+		}, []string{"result"}),
+		expiringProducts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "expiring_products_total",
+			Help:      "Products found expiring and claimed for notification.",
+		}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.dbQueryDuration,
+		m.dbQueryErrors,
+		m.notifications,
+		m.expiringProducts,
+	)
+	// 結果ごとの系列を 0 から出力する
+	for _, result := range []string{resultQueued, resultSent, resultFailed} {
+		m.notifications.WithLabelValues(result)
+	}
+	return m
+}
+
+// RegisterDB exports the connection pool statistics of db.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the metrics in the Prometheus text format. When token is
+// not empty, scrapes must send it as a bearer token.
+func (m *Metrics) Handler(token string) echo.HandlerFunc {
+	h := promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+	return func(c echo.Context) error {
+		if token != "" {
+			got := c.Request().Header.Get(echo.HeaderAuthorization)
+			if subtle.ConstantTimeCompare([]byte(got), []byte("Bearer "+token)) != 1 {
+				return echo.NewHTTPError(http.StatusUnauthorized)
+			}
+		}
+		h.ServeHTTP(c.Response(), c.Request())
+		return nil
+	}
+}
+
+// ExpiringProducts counts products claimed for notification in a scan.
+func (m *Metrics) ExpiringProducts(n int) {
+	m.expiringProducts.Add(float64(n))
+}
+
+// NotificationQueued counts a notification about to be sent to a recipient.
+func (m *Metrics) NotificationQueued() {
+	m.notifications.WithLabelValues(resultQueued).Inc()
+}
+
+func (m *Metrics) NotificationSent() {
+	m.notifications.WithLabelValues(resultSent).Inc()
+}
+
+func (m *Metrics) NotificationFailed() {
+	m.notifications.WithLabelValues(resultFailed).Inc()
+}
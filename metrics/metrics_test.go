@@ -0,0 +1,184 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+	metric := &dto.Metric{}
+	if err := c.Write(metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func histogramCount(t *testing.T, h prometheus.Observer) uint64 {
+	t.Helper()
+	metric := &dto.Metric{}
+	if err := h.(prometheus.Metric).Write(metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestMiddleware(t *testing.T) {
+	m := New()
+	e := echo.New()
+	e.Use(m.Middleware())
+	e.GET("/products/:productId", func(c echo.Context) error {
+		if c.Param("productId") == "0" {
+			return echo.NewHTTPError(http.StatusNotFound, "product not found")
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, path := range []string{"/products/1", "/products/2", "/products/0", "/unknown"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	tests := []struct {
+		name   string
+		labels []string
+		want   float64
+	}{
+		{name: "パスのパラメータをまとめて記録する", labels: []string{"GET", "/products/:productId", "200"}, want: 2},
+		{name: "ハンドラーのエラーはステータスコードに反映する", labels: []string{"GET", "/products/:productId", "404"}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := counterValue(t, m.httpRequests.WithLabelValues(tt.labels...)); got != tt.want {
+				t.Errorf("http_requests_total%v = %v, want %v", tt.labels, got, tt.want)
+			}
+			if got := histogramCount(t, m.httpDuration.WithLabelValues(tt.labels...)); got != uint64(tt.want) {
+				t.Errorf("http_request_duration_seconds%v count = %v, want %v", tt.labels, got, tt.want)
+			}
+		})
+	}
+
+	total := 0.0
+	ch := make(chan prometheus.Metric, 10)
+	m.httpRequests.Collect(ch)
+	close(ch)
+	for metric := range ch {
+		d := &dto.Metric{}
+		metric.Write(d)
+		total += d.GetCounter().GetValue()
+	}
+	if total != 4 {
+		t.Errorf("http_requests_total = %v in all, want 4 including the unmatched request", total)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.NotificationSent()
+	e := echo.New()
+	e.GET("/metrics", m.Handler("secret"))
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "トークンなし", wantStatus: http.StatusUnauthorized},
+		{name: "誤ったトークン", authorization: "Bearer wrong", wantStatus: http.StatusUnauthorized},
+		{name: "正しいトークン", authorization: "Bearer secret", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && !strings.Contains(rec.Body.String(), `expiry_tracker_notifications_total{result="sent"} 1`) {
+				t.Errorf("body does not contain the notification counter:\n%s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGormPlugin(t *testing.T) {
+	m := New()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		// SQL を組み立てるだけでデータベースには接続しない
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(m.GormPlugin()); err != nil {
+		t.Fatalf("Use() error = %v", err)
+	}
+
+	type Product struct {
+		ID   uint
+		Name string
+	}
+	db.Create(&Product{Name: "milk"})
+	db.Where("id = ?", 1).Find(&[]Product{})
+	db.Where("id = ?", 1).Find(&[]Product{})
+
+	if got := histogramCount(t, m.dbQueryDuration.WithLabelValues("create", "products")); got != 1 {
+		t.Errorf("create count = %d, want 1", got)
+	}
+	if got := histogramCount(t, m.dbQueryDuration.WithLabelValues("query", "products")); got != 2 {
+		t.Errorf("query count = %d, want 2", got)
+	}
+
+	// 削除の実行に失敗したことにする
+	if err := db.Callback().Delete().Before("gorm:delete").Register("test:fail", func(db *gorm.DB) {
+		db.AddError(errors.New("connection reset"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	db.Delete(&Product{ID: 1})
+	if got := counterValue(t, m.dbQueryErrors.WithLabelValues("delete", "products")); got != 1 {
+		t.Errorf("delete errors = %v, want 1", got)
+	}
+}
+
+func TestNotificationMetrics(t *testing.T) {
+	m := New()
+	m.ExpiringProducts(3)
+	m.NotificationQueued()
+	m.NotificationQueued()
+	m.NotificationSent()
+	m.NotificationFailed()
+
+	tests := []struct {
+		name    string
+		counter prometheus.Counter
+		want    float64
+	}{
+		{name: "期限間近の製品", counter: m.expiringProducts, want: 3},
+		{name: "送信待ち", counter: m.notifications.WithLabelValues(resultQueued), want: 2},
+		{name: "送信済み", counter: m.notifications.WithLabelValues(resultSent), want: 1},
+		{name: "送信失敗", counter: m.notifications.WithLabelValues(resultFailed), want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := counterValue(t, tt.counter); got != tt.want {
+				t.Errorf("counter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
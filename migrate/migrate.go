@@ -1,14 +1,126 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"expiry_tracker/config"
 	"expiry_tracker/db"
-	"expiry_tracker/model"
+	"expiry_tracker/migrations"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
 )
 
+const usage = `Usage: go run ./migrate [-dir migrations] <command>
+
+Commands:
+  up            apply all pending migrations (default)
+  down [N]      roll back the latest N migrations (default 1)
+  status        list migrations and whether they are applied
+  create NAME   add an empty migration to -dir
+`
+
 func main() {
-	dbConn := db.NewDB()
-	defer fmt.Println("Successfully Migarated")
-	defer db.CloseDB(dbConn)
-	dbConn.AutoMigrate(&model.User{}, &model.Product{})
-}
\ No newline at end of file
+	dir := flag.String("dir", "migrations", "directory to create migrations in")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	command := flag.Arg(0)
+	if command == "" {
+		command = "up"
+	}
+	if command == "create" {
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		upPath, downPath, err := migrations.Create(*dir, flag.Arg(1))
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("Created %s\nCreated %s\n", upPath, downPath)
+		return
+	}
+
+	steps := 1
+	switch {
+	case command == "down" && flag.NArg() == 2:
+		n, err := strconv.Atoi(flag.Arg(1))
+		if err != nil || n < 1 {
+			log.Fatalf("invalid number of migrations: %s", flag.Arg(1))
+		}
+		steps = n
+	case (command == "up" || command == "down" || command == "status") && flag.NArg() <= 1:
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	embedded, err := migrations.Embedded()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// マイグレーションにはデータベースの設定だけがあればよい
+	if err := cfg.Database.Validate(); err != nil {
+		log.Fatalln(err)
+	}
+	dbConn := db.NewDB(cfg.Database)
+	defer func() {
+		if err := db.CloseDB(dbConn); err != nil {
+			log.Println(err)
+		}
+	}()
+	sqlDB, err := dbConn.DB()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	runner := migrations.NewRunner(sqlDB, embedded)
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := runner.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+	case "down":
+		rolledBack, err := runner.Down(ctx, steps)
+		for _, m := range rolledBack {
+			fmt.Printf("Rolled back %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if len(rolledBack) == 0 {
+			fmt.Println("No applied migrations")
+		}
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "-"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Local().Format(time.DateTime)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, s.State, appliedAt)
+		}
+		w.Flush()
+	}
+}
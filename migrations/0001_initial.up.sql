@@ -0,0 +1,336 @@
+-- 初期スキーマ。AutoMigrate で作成した既存のデータベースにもそのまま適用できるよう、
+-- テーブルとインデックスは IF NOT EXISTS で作成し、既存のテーブルに後から追加された列は
+-- インデックスを作成する前に ADD COLUMN IF NOT EXISTS で補う
+-- household_id は既存のデータを世帯に移すまで NULL を許し、最後に NOT NULL と外部キーを付ける
+
+-- AutoMigrate で作成済みだったテーブルを記録し、down で既存のデータを削除しないようにする
+CREATE TABLE "schema_adopted_tables" (
+    "table_name" text,
+    PRIMARY KEY ("table_name")
+);
+INSERT INTO schema_adopted_tables (table_name)
+SELECT table_name FROM information_schema.tables
+WHERE table_schema = current_schema()
+  AND table_name IN (
+    'users', 'households', 'household_members', 'household_invitations', 'sessions',
+    'password_resets', 'email_verifications', 'attempt_counters', 'recovery_codes', 'api_tokens',
+    'user_identities', 'categories', 'storage_locations', 'products', 'consumption_events'
+  );
+
+-- 削除済みユーザーのメールアドレスで再登録できるよう、一意制約を未削除のユーザーに限定する
+DROP INDEX IF EXISTS idx_users_email;
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "email" text NOT NULL,
+    "password" text,
+    "name" text NOT NULL,
+    "time_zone" text NOT NULL DEFAULT 'Asia/Tokyo',
+    "email_verified_at" timestamptz,
+    "totp_secret" text NOT NULL DEFAULT '',
+    "totp_enabled_at" timestamptz,
+    "totp_last_step" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "time_zone" text NOT NULL DEFAULT 'Asia/Tokyo';
+-- メールアドレスの確認の導入前に登録したユーザーは、通知が止まらないよう確認済みとして扱う
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'email_verified_at'
+    ) THEN
+        ALTER TABLE "users" ADD COLUMN "email_verified_at" timestamptz;
+        UPDATE users SET email_verified_at = COALESCE(created_at, now());
+    END IF;
+END $$;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "totp_secret" text NOT NULL DEFAULT '';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "totp_enabled_at" timestamptz;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "totp_last_step" bigint NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email_active" ON "users" ("email") WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS "households" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_households_deleted_at" ON "households" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "household_members" (
+    "id" bigserial,
+    "household_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "role" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_household_members_household" FOREIGN KEY ("household_id") REFERENCES "households"("id"),
+    CONSTRAINT "fk_household_members_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_household_members_user_id" ON "household_members" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_household_members_household_user" ON "household_members" ("household_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "household_invitations" (
+    "id" bigserial,
+    "household_id" bigint NOT NULL,
+    "inviter_id" bigint NOT NULL,
+    "email" text,
+    "role" text NOT NULL,
+    "token_hash" text NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "accepted_at" timestamptz,
+    "accepted_by" bigint,
+    "declined_at" timestamptz,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_household_invitations_household" FOREIGN KEY ("household_id") REFERENCES "households"("id"),
+    CONSTRAINT "fk_household_invitations_inviter" FOREIGN KEY ("inviter_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_household_invitations_household_id" ON "household_invitations" ("household_id");
+
+CREATE TABLE IF NOT EXISTS "sessions" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "refresh_token_hash" text NOT NULL,
+    "user_agent" text,
+    "ip_address" text,
+    "last_used_at" timestamptz,
+    "expires_at" timestamptz NOT NULL,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_sessions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sessions_user_id" ON "sessions" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "password_resets" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "token_hash" text NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_password_resets_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_password_resets_user_id" ON "password_resets" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "email_verifications" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "email" text NOT NULL,
+    "token_hash" text NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_email_verifications_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_email_verifications_user_id" ON "email_verifications" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "attempt_counters" (
+    "key" text,
+    "count" bigint NOT NULL,
+    "last_attempt_at" timestamptz NOT NULL,
+    PRIMARY KEY ("key")
+);
+
+CREATE TABLE IF NOT EXISTS "recovery_codes" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "code_hash" text NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_recovery_codes_user_id" ON "recovery_codes" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "api_tokens" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "token_hash" text NOT NULL,
+    "scope" text NOT NULL,
+    "expires_at" timestamptz,
+    "last_used_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_api_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_api_tokens_token_hash" ON "api_tokens" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_api_tokens_user_id" ON "api_tokens" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "user_identities" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "issuer" text NOT NULL,
+    "subject" text NOT NULL,
+    "email" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_identities_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_identities_subject" ON "user_identities" ("issuer","subject");
+CREATE INDEX IF NOT EXISTS "idx_user_identities_user_id" ON "user_identities" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "categories" (
+    "id" bigserial,
+    "household_id" bigint,
+    "user_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "freezer_shelf_life_days" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_categories_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+ALTER TABLE "categories" ADD COLUMN IF NOT EXISTS "household_id" bigint;
+ALTER TABLE "categories" ADD COLUMN IF NOT EXISTS "freezer_shelf_life_days" bigint;
+CREATE INDEX IF NOT EXISTS "idx_categories_deleted_at" ON "categories" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_categories_user_id" ON "categories" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_categories_household_id" ON "categories" ("household_id");
+
+CREATE TABLE IF NOT EXISTS "storage_locations" (
+    "id" bigserial,
+    "household_id" bigint,
+    "user_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "kind" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_storage_locations_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+ALTER TABLE "storage_locations" ADD COLUMN IF NOT EXISTS "household_id" bigint;
+CREATE INDEX IF NOT EXISTS "idx_storage_locations_deleted_at" ON "storage_locations" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_storage_locations_user_id" ON "storage_locations" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_storage_locations_household_id" ON "storage_locations" ("household_id");
+
+CREATE TABLE IF NOT EXISTS "products" (
+    "id" bigserial,
+    "household_id" bigint,
+    "user_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "description" text,
+    "quantity" bigint DEFAULT 1,
+    "expiry_date" timestamptz NOT NULL,
+    "type" text NOT NULL,
+    "is_notified" boolean DEFAULT false,
+    "archived_at" timestamptz,
+    "opened_at" timestamptz,
+    "days_after_opening" bigint,
+    "frozen_at" timestamptz,
+    "thawed_at" timestamptz,
+    "freezer_shelf_life_days" bigint,
+    "effective_expiry_date" timestamptz,
+    "category_id" bigint,
+    "storage_location_id" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_products_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_products_category" FOREIGN KEY ("category_id") REFERENCES "categories"("id") ON DELETE SET NULL,
+    CONSTRAINT "fk_products_storage_location" FOREIGN KEY ("storage_location_id") REFERENCES "storage_locations"("id") ON DELETE SET NULL
+);
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "household_id" bigint;
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "archived_at" timestamptz;
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "opened_at" timestamptz;
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "days_after_opening" bigint;
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "frozen_at" timestamptz;
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "thawed_at" timestamptz;
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "freezer_shelf_life_days" bigint;
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "effective_expiry_date" timestamptz;
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "category_id" bigint
+    CONSTRAINT "fk_products_category" REFERENCES "categories"("id") ON DELETE SET NULL;
+ALTER TABLE "products" ADD COLUMN IF NOT EXISTS "storage_location_id" bigint
+    CONSTRAINT "fk_products_storage_location" REFERENCES "storage_locations"("id") ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS "idx_products_deleted_at" ON "products" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_products_storage_location_id" ON "products" ("storage_location_id");
+CREATE INDEX IF NOT EXISTS "idx_products_category_id" ON "products" ("category_id");
+CREATE INDEX IF NOT EXISTS "idx_products_effective_expiry_date" ON "products" ("effective_expiry_date");
+CREATE INDEX IF NOT EXISTS "idx_products_archived_at" ON "products" ("archived_at");
+CREATE INDEX IF NOT EXISTS "idx_products_household_id" ON "products" ("household_id");
+
+CREATE TABLE IF NOT EXISTS "consumption_events" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "kind" text NOT NULL,
+    "amount" bigint NOT NULL,
+    "occurred_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_consumption_events_product" FOREIGN KEY ("product_id") REFERENCES "products"("id"),
+    CONSTRAINT "fk_consumption_events_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_consumption_events_occurred_at" ON "consumption_events" ("occurred_at");
+CREATE INDEX IF NOT EXISTS "idx_consumption_events_product_id" ON "consumption_events" ("product_id");
+
+-- 既存の製品は印字された期限をそのまま実効期限とする
+UPDATE products SET effective_expiry_date = expiry_date WHERE effective_expiry_date IS NULL;
+
+-- 世帯に所属していないユーザーには個人用の世帯を作成する。削除済みのユーザーも、
+-- 世帯に移すデータが残っていれば対象にする
+DO $$
+DECLARE
+    u RECORD;
+    new_household_id bigint;
+BEGIN
+    FOR u IN
+        SELECT id FROM users
+        WHERE NOT EXISTS (SELECT 1 FROM household_members WHERE household_members.user_id = users.id)
+          AND (
+            deleted_at IS NULL
+            OR EXISTS (SELECT 1 FROM products WHERE products.user_id = users.id AND products.household_id IS NULL)
+            OR EXISTS (SELECT 1 FROM categories WHERE categories.user_id = users.id AND categories.household_id IS NULL)
+            OR EXISTS (SELECT 1 FROM storage_locations WHERE storage_locations.user_id = users.id AND storage_locations.household_id IS NULL)
+          )
+    LOOP
+        INSERT INTO households (name, created_at, updated_at) VALUES ('Home', now(), now())
+            RETURNING id INTO new_household_id;
+        INSERT INTO household_members (household_id, user_id, role, created_at, updated_at)
+            VALUES (new_household_id, u.id, 'owner', now(), now());
+    END LOOP;
+END $$;
+
+-- 世帯の導入前のデータを所有者の最初の世帯に移す
+UPDATE products SET household_id = (
+    SELECT household_members.household_id FROM household_members
+    WHERE household_members.user_id = products.user_id
+    ORDER BY household_members.created_at, household_members.id LIMIT 1
+) WHERE household_id IS NULL;
+UPDATE categories SET household_id = (
+    SELECT household_members.household_id FROM household_members
+    WHERE household_members.user_id = categories.user_id
+    ORDER BY household_members.created_at, household_members.id LIMIT 1
+) WHERE household_id IS NULL;
+UPDATE storage_locations SET household_id = (
+    SELECT household_members.household_id FROM household_members
+    WHERE household_members.user_id = storage_locations.user_id
+    ORDER BY household_members.created_at, household_members.id LIMIT 1
+) WHERE household_id IS NULL;
+
+-- 移し終えてから、すべてのデータが世帯に属することを制約で保証する
+ALTER TABLE "products" ALTER COLUMN "household_id" SET NOT NULL;
+ALTER TABLE "products" ADD CONSTRAINT "fk_products_household" FOREIGN KEY ("household_id") REFERENCES "households"("id");
+ALTER TABLE "categories" ALTER COLUMN "household_id" SET NOT NULL;
+ALTER TABLE "categories" ADD CONSTRAINT "fk_categories_household" FOREIGN KEY ("household_id") REFERENCES "households"("id");
+ALTER TABLE "storage_locations" ALTER COLUMN "household_id" SET NOT NULL;
+ALTER TABLE "storage_locations" ADD CONSTRAINT "fk_storage_locations_household" FOREIGN KEY ("household_id") REFERENCES "households"("id");
@@ -0,0 +1,15 @@
+-- 期限の通知を製品・ユーザー・実効期限ごとに記録し、送信済みのメンバーに再送しないようにする
+CREATE TABLE IF NOT EXISTS "expiry_notifications" (
+    "id" bigserial,
+    "product_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "expiry_date" timestamptz NOT NULL,
+    "claimed_at" timestamptz NOT NULL,
+    "sent_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_expiry_notifications_product" FOREIGN KEY ("product_id") REFERENCES "products"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_expiry_notifications_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_expiry_notifications_delivery" ON "expiry_notifications" ("product_id","user_id","expiry_date");
+CREATE INDEX IF NOT EXISTS "idx_expiry_notifications_user_id" ON "expiry_notifications" ("user_id");
@@ -0,0 +1,142 @@
+// Package migrations applies the versioned SQL migrations in this directory,
+// which are embedded in the binary. Each migration is a pair of files,
+// NNNN_name.up.sql and NNNN_name.down.sql, applied in version order.
+//
+// A migration whose up file starts with the line "-- migrate:no-transaction"
+// runs outside a transaction, for statements such as CREATE INDEX
+// CONCURRENTLY that PostgreSQL does not allow inside one.
+package migrations
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+const noTransactionDirective = "-- migrate:no-transaction"
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version       int64
+	Name          string
+	Up            string
+	Down          string
+	Checksum      string // up ファイルの SHA-256。適用後に書き換えられていないかの確認に使う
+	NoTransaction bool
+}
+
+// Embedded returns the migrations embedded in the binary.
+func Embedded() ([]Migration, error) {
+	return Load(files)
+}
+
+// Load reads the migrations in the root of fsys, sorted by version. Every
+// version must have both an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		m := fileNamePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("migrations: invalid file name %s, want NNNN_name.up.sql or NNNN_name.down.sql", entry.Name())
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migrations: invalid version in %s", entry.Name())
+		}
+		b, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != m[2] {
+			return nil, fmt.Errorf("migrations: version %d is used by both %s and %s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(b)
+			sum := sha256.Sum256(b)
+			migration.Checksum = hex.EncodeToString(sum[:])
+			migration.NoTransaction = strings.HasPrefix(migration.Up, noTransactionDirective)
+		} else {
+			migration.Down = string(b)
+		}
+	}
+
+	migrations := []Migration{}
+	for _, m := range byVersion {
+		if m.Checksum == "" {
+			return nil, fmt.Errorf("migrations: %04d_%s has no up file", m.Version, m.Name)
+		}
+		if m.Down == "" {
+			return nil, fmt.Errorf("migrations: %04d_%s has no down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create writes an empty pair of files for a new migration to dir, numbered
+// after the latest migration there, and returns their paths.
+func Create(dir string, name string) (string, string, error) {
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migrations: name must contain letters or digits")
+	}
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	version := int64(1)
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%04d_%s", version, name))
+	upPath := base + ".up.sql"
+	downPath := base + ".down.sql"
+	if err := writeNewFile(upPath, "-- "+name+"\n"); err != nil {
+		return "", "", err
+	}
+	if err := writeNewFile(downPath, "-- "+name+" を取り消す\n"); err != nil {
+		return "", "", err
+	}
+	return upPath, downPath, nil
+}
+
+func writeNewFile(path string, content string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
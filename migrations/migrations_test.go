@@ -0,0 +1,207 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		wantErr      bool
+		wantVersions []int64
+	}{
+		{
+			name: "バージョン順に読み込む",
+			files: fstest.MapFS{
+				"0002_add_index.up.sql":   {Data: []byte("CREATE INDEX a ON b (c);")},
+				"0002_add_index.down.sql": {Data: []byte("DROP INDEX a;")},
+				"0001_initial.up.sql":     {Data: []byte("CREATE TABLE b (c int);")},
+				"0001_initial.down.sql":   {Data: []byte("DROP TABLE b;")},
+				"migrations.go":           {Data: []byte("package migrations")},
+			},
+			wantVersions: []int64{1, 2},
+		},
+		{
+			name: "down ファイルがない",
+			files: fstest.MapFS{
+				"0001_initial.up.sql": {Data: []byte("CREATE TABLE b (c int);")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "同じバージョンに別の名前",
+			files: fstest.MapFS{
+				"0001_initial.up.sql": {Data: []byte("CREATE TABLE b (c int);")},
+				"0001_other.down.sql": {Data: []byte("DROP TABLE b;")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ファイル名が不正",
+			files: fstest.MapFS{
+				"initial.sql": {Data: []byte("CREATE TABLE b (c int);")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := Load(tt.files)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Load() error = nil, wantErr %v", tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(migrations) != len(tt.wantVersions) {
+				t.Fatalf("Load() returned %d migrations, want %d", len(migrations), len(tt.wantVersions))
+			}
+			for i, m := range migrations {
+				if m.Version != tt.wantVersions[i] {
+					t.Errorf("migrations[%d].Version = %d, want %d", i, m.Version, tt.wantVersions[i])
+				}
+				if m.Up == "" || m.Down == "" || len(m.Checksum) != 64 {
+					t.Errorf("migrations[%d] = %+v, want up, down and checksum", i, m)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_Checksum(t *testing.T) {
+	load := func(up string) Migration {
+		migrations, err := Load(fstest.MapFS{
+			"0001_initial.up.sql":   {Data: []byte(up)},
+			"0001_initial.down.sql": {Data: []byte("DROP TABLE b;")},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return migrations[0]
+	}
+
+	if load("CREATE TABLE b (c int);").Checksum == load("CREATE TABLE b (c bigint);").Checksum {
+		t.Error("Checksum is the same for different up files")
+	}
+	if load("CREATE TABLE b (c int);").NoTransaction {
+		t.Error("NoTransaction = true without the directive")
+	}
+	if !load(noTransactionDirective + "\nCREATE INDEX CONCURRENTLY a ON b (c);").NoTransaction {
+		t.Error("NoTransaction = false with the directive")
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	migrations, err := Embedded()
+	if err != nil {
+		t.Fatalf("Embedded() error = %v", err)
+	}
+	if len(migrations) == 0 || migrations[0].Version != 1 {
+		t.Fatalf("Embedded() = %d migrations, want the initial migration first", len(migrations))
+	}
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version != migrations[i-1].Version+1 {
+			t.Errorf("migration %d follows %d, want consecutive versions", migrations[i].Version, migrations[i-1].Version)
+		}
+	}
+}
+
+func TestEmbedded_AddsColumnsToExistingTables(t *testing.T) {
+	// AutoMigrate で作成済みのテーブルと、その時点で既にあった列
+	existing := map[string][]string{
+		"users":             {"id", "email", "password", "name", "created_at", "updated_at", "deleted_at"},
+		"products":          {"id", "user_id", "name", "description", "quantity", "expiry_date", "type", "is_notified", "created_at", "updated_at", "deleted_at"},
+		"categories":        {"id", "user_id", "name", "created_at", "updated_at", "deleted_at"},
+		"storage_locations": {"id", "user_id", "name", "kind", "created_at", "updated_at", "deleted_at"},
+	}
+
+	migrations, err := Embedded()
+	if err != nil {
+		t.Fatalf("Embedded() error = %v", err)
+	}
+	up := migrations[0].Up
+	column := regexp.MustCompile(`(?m)^\s+"(\w+)" `)
+	for table, columns := range existing {
+		create := regexp.MustCompile(`(?s)CREATE TABLE IF NOT EXISTS "` + table + `" \((.*?)\n\);`).FindStringSubmatch(up)
+		if create == nil {
+			t.Fatalf("CREATE TABLE %s not found", table)
+		}
+		firstIndex := regexp.MustCompile(`INDEX IF NOT EXISTS "\w+" ON "` + table + `"`).FindStringIndex(up)
+		if firstIndex == nil {
+			t.Fatalf("no index on %s", table)
+		}
+		for _, m := range column.FindAllStringSubmatch(create[1], -1) {
+			name := m[1]
+			if slices.Contains(columns, name) {
+				continue
+			}
+			add := regexp.MustCompile(`ALTER TABLE "` + table + `" ADD COLUMN (IF NOT EXISTS )?"` + name + `"`).FindStringIndex(up)
+			if add == nil || add[0] > firstIndex[0] {
+				t.Errorf("%s.%s is not added to existing tables before the indexes", table, name)
+			}
+		}
+	}
+}
+
+func TestEmbedded_InitialDownKeepsAdoptedTables(t *testing.T) {
+	migrations, err := Embedded()
+	if err != nil {
+		t.Fatalf("Embedded() error = %v", err)
+	}
+	up, down := migrations[0].Up, migrations[0].Down
+	adopted := regexp.MustCompile(`(?s)INSERT INTO schema_adopted_tables .*?\);`).FindString(up)
+	if adopted == "" {
+		t.Fatal("adopted tables are not recorded")
+	}
+	// 作成するテーブルは既存かどうかを記録し、down で削除する
+	for _, m := range regexp.MustCompile(`CREATE TABLE IF NOT EXISTS "(\w+)"`).FindAllStringSubmatch(up, -1) {
+		table := m[1]
+		if !strings.Contains(adopted, "'"+table+"'") {
+			t.Errorf("%s is not recorded as adopted", table)
+		}
+		if !strings.Contains(down, `DROP TABLE IF EXISTS "`+table+`"`) {
+			t.Errorf("%s is not dropped by down", table)
+		}
+	}
+	if strings.Index(down, "RAISE EXCEPTION") > strings.Index(down, "DROP TABLE") {
+		t.Error("down drops tables before checking for adopted tables")
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+
+	upPath, downPath, err := Create(dir, "Add products index")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if filepath.Base(upPath) != "0001_add_products_index.up.sql" || filepath.Base(downPath) != "0001_add_products_index.down.sql" {
+		t.Errorf("Create() = %s, %s", upPath, downPath)
+	}
+
+	upPath, _, err = Create(dir, "second")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if filepath.Base(upPath) != "0002_second.up.sql" {
+		t.Errorf("Create() = %s, want version 0002", upPath)
+	}
+	if _, err := os.Stat(upPath); err != nil {
+		t.Errorf("up file was not written: %v", err)
+	}
+
+	if _, _, err := Create(dir, "!!!"); err == nil {
+		t.Error("Create() error = nil for a name without letters or digits")
+	}
+}
@@ -0,0 +1,257 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// lockKey identifies the advisory lock that keeps two runners, e.g. two
+// instances starting at the same time, from migrating concurrently.
+const lockKey int64 = 7_352_641_009
+
+// Migration states reported by Status.
+const (
+	StateApplied  = "applied"
+	StatePending  = "pending"
+	StateModified = "modified" // 適用後に up ファイルが書き換えられた
+	StateMissing  = "missing"  // 適用済みだがファイルがない
+)
+
+type Status struct {
+	Version   int64
+	Name      string
+	State     string
+	AppliedAt *time.Time
+}
+
+type IRunner interface {
+	Up(ctx context.Context) ([]Migration, error)
+	Down(ctx context.Context, steps int) ([]Migration, error)
+	Status(ctx context.Context) ([]Status, error)
+	Current(ctx context.Context) (int64, error)
+}
+
+type runner struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewRunner(db *sql.DB, migrations []Migration) IRunner {
+	return &runner{db: db, migrations: migrations}
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Up applies all pending migrations in version order and returns them. It
+// refuses to run while an applied migration has been modified or removed.
+func (r *runner) Up(ctx context.Context) ([]Migration, error) {
+	done := []Migration{}
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := r.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range r.migrations {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			if err := run(ctx, conn, m.NoTransaction, m.Up,
+				"INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES ($1, $2, $3, $4)",
+				m.Version, m.Name, m.Checksum, time.Now()); err != nil {
+				return fmt.Errorf("migrations: %04d_%s: %w", m.Version, m.Name, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back the latest steps applied migrations and returns them.
+func (r *runner) Down(ctx context.Context, steps int) ([]Migration, error) {
+	done := []Migration{}
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := r.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(r.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			m := r.migrations[i]
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+			if err := run(ctx, conn, m.NoTransaction, m.Down,
+				"DELETE FROM schema_migrations WHERE version = $1", m.Version); err != nil {
+				return fmt.Errorf("migrations: %04d_%s: %w", m.Version, m.Name, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status lists every known migration and every applied one, in version
+// order.
+func (r *runner) Status(ctx context.Context) ([]Status, error) {
+	statuses := []Status{}
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+		known := map[int64]bool{}
+		for _, m := range r.migrations {
+			known[m.Version] = true
+			status := Status{Version: m.Version, Name: m.Name, State: StatePending}
+			if a, ok := applied[m.Version]; ok {
+				status.State = StateApplied
+				if a.checksum != m.Checksum {
+					status.State = StateModified
+				}
+				status.AppliedAt = &a.appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		for version, a := range applied {
+			if !known[version] {
+				statuses = append(statuses, Status{Version: version, Name: a.name, State: StateMissing, AppliedAt: &a.appliedAt})
+			}
+		}
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+		return nil
+	})
+	return statuses, err
+}
+
+// verify returns the applied migrations after checking that each of them is
+// still present and unchanged.
+func (r *runner) verify(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	known := map[int64]Migration{}
+	for _, m := range r.migrations {
+		known[m.Version] = m
+	}
+	for version, a := range applied {
+		m, ok := known[version]
+		if !ok {
+			return nil, fmt.Errorf("migrations: %04d_%s is applied but its files are missing", version, a.name)
+		}
+		if m.Checksum != a.checksum {
+			return nil, fmt.Errorf("migrations: %04d_%s has been modified after it was applied", version, m.Name)
+		}
+	}
+	return applied, nil
+}
+
+// Current returns the version of the latest applied migration, or an error
+// unless every known migration is applied unmodified and no unknown one is.
+// It does not take the lock, so it can be polled while another runner is
+// migrating.
+func (r *runner) Current(ctx context.Context) (int64, error) {
+	applied, err := appliedMigrations(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	var current int64
+	for _, m := range r.migrations {
+		a, ok := applied[m.Version]
+		if !ok {
+			return current, fmt.Errorf("migration %04d_%s is pending", m.Version, m.Name)
+		}
+		if a.checksum != m.Checksum {
+			return current, fmt.Errorf("migration %04d_%s was modified after it was applied", m.Version, m.Name)
+		}
+		current = m.Version
+	}
+	if len(applied) > len(r.migrations) {
+		return current, fmt.Errorf("database has migrations this build does not know")
+	}
+	return current, nil
+}
+
+// withLock runs fn on a dedicated connection holding the advisory lock.
+// Session-level advisory locks belong to a connection, so the lock, the
+// migrations and the unlock must all use the same one.
+func (r *runner) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		checksum text NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// queryer is implemented by both *sql.DB and *sql.Conn.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func appliedMigrations(ctx context.Context, conn queryer) (map[int64]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]appliedMigration{}
+	for rows.Next() {
+		var version int64
+		a := appliedMigration{}
+		if err := rows.Scan(&version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = a
+	}
+	return applied, rows.Err()
+}
+
+// run executes script and then the bookkeeping statement, in one transaction
+// unless noTransaction is set.
+func run(ctx context.Context, conn *sql.Conn, noTransaction bool, script string, query string, args ...interface{}) error {
+	if noTransaction {
+		if _, err := conn.ExecContext(ctx, script); err != nil {
+			return err
+		}
+		_, err := conn.ExecContext(ctx, query, args...)
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
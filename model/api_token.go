@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+)
+
+// ApiToken はスクリプトや外部サービスから API を呼び出すための個人用トークン。
+// トークンは SHA-256 のハッシュのみ保存する
+type ApiToken struct {
+	ID         uint          `json:"id" gorm:"primaryKey"`
+	UserId     uint          `json:"user_id" gorm:"not null;index"`
+	User       User          `json:"user" gorm:"foreignKey:UserId"`
+	Name       string        `json:"name" gorm:"not null"`
+	TokenHash  string        `json:"-" gorm:"not null;uniqueIndex"`
+	Scope      ApiTokenScope `json:"scope" gorm:"not null"`
+	ExpiresAt  *time.Time    `json:"expires_at"` // nil の場合は無期限
+	LastUsedAt *time.Time    `json:"last_used_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+type ApiTokenScope string
+
+const (
+	ApiTokenScopeRead  ApiTokenScope = "read"  // GET のみ
+	ApiTokenScopeWrite ApiTokenScope = "write" // 読み書き
+)
+
+const (
+	// ApiTokenPrefix はログやソースコードに紛れたトークンを見つけやすくするための接頭辞
+	ApiTokenPrefix       = "fk_"
+	MaxApiTokensPerUser  = 20
+	MaxApiTokenExpiresIn = 365
+	// ApiTokenTouchInterval より短い間隔では last_used_at を更新しない
+	ApiTokenTouchInterval = time.Minute
+)
+
+// ApiTokenRequest は POST /tokens のリクエストボディ
+type ApiTokenRequest struct {
+	Name          string        `json:"name"`
+	Scope         ApiTokenScope `json:"scope"`
+	ExpiresInDays int           `json:"expires_in_days"` // 省略時は無期限
+}
+
+type ApiTokenResponse struct {
+	ID         uint          `json:"id"`
+	Name       string        `json:"name"`
+	Scope      ApiTokenScope `json:"scope"`
+	ExpiresAt  *time.Time    `json:"expires_at"`
+	LastUsedAt *time.Time    `json:"last_used_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+	// Token は作成時のみ返す
+	Token string `json:"token,omitempty"`
+}
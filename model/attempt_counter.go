@@ -0,0 +1,12 @@
+package model
+
+import (
+	"time"
+)
+
+// AttemptCounter はログイン失敗などの試行回数をキー（IP アドレスやアカウント）ごとに数える
+type AttemptCounter struct {
+	Key           string    `json:"key" gorm:"primaryKey"`
+	Count         int       `json:"count" gorm:"not null"`
+	LastAttemptAt time.Time `json:"last_attempt_at" gorm:"not null"`
+}
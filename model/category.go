@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Category struct {
+	ID                   uint           `json:"id" gorm:"primaryKey"`
+	HouseholdId          uint           `json:"household_id" gorm:"not null;index"`
+	UserId               uint           `json:"user_id" gorm:"not null;index"` // 作成したユーザー
+	User                 User           `json:"user" gorm:"foreignKey:UserId"`
+	Name                 string         `json:"name" gorm:"not null"`
+	FreezerShelfLifeDays *int           `json:"freezer_shelf_life_days"` // 冷凍保存できる日数（未設定なら既定値）
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type CategoryResponse struct {
+	ID                   uint      `json:"id"`
+	Name                 string    `json:"name"`
+	FreezerShelfLifeDays *int      `json:"freezer_shelf_life_days"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+type ConsumptionEventKind string
+
+const (
+	ConsumptionEventConsumed ConsumptionEventKind = "consumed" // 消費
+	ConsumptionEventWasted   ConsumptionEventKind = "wasted"   // 廃棄
+)
+
+type ConsumptionEvent struct {
+	ID         uint                 `json:"id" gorm:"primaryKey"`
+	ProductId  uint                 `json:"product_id" gorm:"not null;index"`
+	Product    Product              `json:"-" gorm:"foreignKey:ProductId"`
+	UserId     uint                 `json:"user_id" gorm:"not null"`
+	User       User                 `json:"-" gorm:"foreignKey:UserId"`
+	Kind       ConsumptionEventKind `json:"kind" gorm:"not null"`
+	Amount     int                  `json:"amount" gorm:"not null"`
+	OccurredAt time.Time            `json:"occurred_at" gorm:"not null;index"`
+	CreatedAt  time.Time            `json:"created_at"`
+}
+
+// QuantityAdjustment は消費・廃棄APIのリクエストボディ
+type QuantityAdjustment struct {
+	Amount int `json:"amount"`
+}
+
+type ConsumptionEventResponse struct {
+	ID         uint                 `json:"id"`
+	ProductId  uint                 `json:"product_id"`
+	Kind       ConsumptionEventKind `json:"kind"`
+	Amount     int                  `json:"amount"`
+	OccurredAt time.Time            `json:"occurred_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+)
+
+// EmailVerification はメールアドレス確認用のワンタイムトークン。トークンはハッシュのみ保存する
+type EmailVerification struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserId    uint       `json:"user_id" gorm:"not null;index"`
+	User      User       `json:"user" gorm:"foreignKey:UserId"`
+	Email     string     `json:"email" gorm:"not null"` // 確認するアドレス（送信後に変更されていたら無効）
+	TokenHash string     `json:"-" gorm:"not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+const (
+	EmailVerificationLifetime = 24 * time.Hour
+	// 確認メールの再送は 1 分に 1 回、1 日 5 回まで
+	EmailVerificationResendInterval = time.Minute
+	EmailVerificationDailyLimit     = 5
+)
+
+// VerifyEmailRequest は POST /email/verify のリクエストボディ
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,15 @@
+package model
+
+// ErrorResponse is the JSON envelope for every error returned by the API.
+// It matches the { message, errors } shape read by the frontend ApiClient.
+type ErrorResponse struct {
+	Message string                `json:"message"`
+	Errors  map[string]FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes why a single request field was rejected.
+// Code is stable and meant for programs; Message is meant for people.
+type FieldError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// ExpiryNotification は製品の期限の通知をユーザーごとに記録する。同じ実効期限については
+// ユーザーごとに 1 回だけ送り、期限が変わると新しい行で再び通知する
+type ExpiryNotification struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	ProductId  uint       `json:"product_id" gorm:"not null;uniqueIndex:idx_expiry_notifications_delivery"`
+	UserId     uint       `json:"user_id" gorm:"not null;uniqueIndex:idx_expiry_notifications_delivery;index"`
+	ExpiryDate time.Time  `json:"expiry_date" gorm:"not null;uniqueIndex:idx_expiry_notifications_delivery"` // 通知した時点の実効期限
+	ClaimedAt  time.Time  `json:"claimed_at" gorm:"not null"`                                                // 送信を確保した日時
+	SentAt     *time.Time `json:"sent_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ExpiryNotificationClaimTimeout は送信を確保したまま送信済みにならなかった通知を、
+// 送信中に停止したものとみなして取り直すまでの時間
+const ExpiryNotificationClaimTimeout = 10 * time.Minute
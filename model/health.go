@@ -0,0 +1,30 @@
+package model
+
+// ヘルスチェックの結果
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+// HealthCheck は readiness の確認項目ごとの結果
+type HealthCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadinessResponse はいずれかの確認項目が失敗していれば Status が fail になる
+type ReadinessResponse struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+// VersionResponse は実行中のバイナリと適用済みのスキーマのバージョン
+type VersionResponse struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	// 最後に適用したマイグレーション。データベースに接続できない場合は null
+	SchemaVersion *int64 `json:"schema_version"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Household struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" gorm:"not null"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type HouseholdRole string
+
+const (
+	HouseholdRoleOwner  HouseholdRole = "owner"  // 世帯と所属メンバーの管理
+	HouseholdRoleEditor HouseholdRole = "editor" // 製品・カテゴリ・保存場所の編集
+	HouseholdRoleViewer HouseholdRole = "viewer" // 閲覧のみ
+)
+
+var householdRoleRank = map[HouseholdRole]int{
+	HouseholdRoleViewer: 1,
+	HouseholdRoleEditor: 2,
+	HouseholdRoleOwner:  3,
+}
+
+// Allows reports whether a member with role r may do what required needs.
+func (r HouseholdRole) Allows(required HouseholdRole) bool {
+	return householdRoleRank[r] >= householdRoleRank[required]
+}
+
+// DefaultHouseholdName は登録時に作成される個人用の世帯名
+const DefaultHouseholdName = "Home"
+
+type HouseholdMember struct {
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	HouseholdId uint          `json:"household_id" gorm:"not null;uniqueIndex:idx_household_members_household_user"`
+	Household   Household     `json:"household" gorm:"foreignKey:HouseholdId"`
+	UserId      uint          `json:"user_id" gorm:"not null;uniqueIndex:idx_household_members_household_user;index"`
+	User        User          `json:"user" gorm:"foreignKey:UserId"`
+	Role        HouseholdRole `json:"role" gorm:"not null"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+type HouseholdResponse struct {
+	ID        uint                      `json:"id"`
+	Name      string                    `json:"name"`
+	Role      HouseholdRole             `json:"role"` // リクエストしたユーザーの役割
+	Members   []HouseholdMemberResponse `json:"members,omitempty"`
+	CreatedAt time.Time                 `json:"created_at"`
+	UpdatedAt time.Time                 `json:"updated_at"`
+}
+
+type HouseholdMemberResponse struct {
+	UserId   uint          `json:"user_id"`
+	Name     string        `json:"name"`
+	Email    string        `json:"email"`
+	Role     HouseholdRole `json:"role"`
+	JoinedAt time.Time     `json:"joined_at"`
+}
+
+// HouseholdMemberRoleRequest は PUT /households/:id/members/:userId のリクエストボディ
+type HouseholdMemberRoleRequest struct {
+	Role HouseholdRole `json:"role"`
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+)
+
+type HouseholdInvitation struct {
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	HouseholdId uint          `json:"household_id" gorm:"not null;index"`
+	Household   Household     `json:"household" gorm:"foreignKey:HouseholdId"`
+	InviterId   uint          `json:"inviter_id" gorm:"not null"`
+	Inviter     User          `json:"inviter" gorm:"foreignKey:InviterId"`
+	Email       string        `json:"email"` // 空の場合はリンクを知っている誰でも参加できる
+	Role        HouseholdRole `json:"role" gorm:"not null"`
+	TokenHash   string        `json:"-" gorm:"not null"`
+	ExpiresAt   time.Time     `json:"expires_at" gorm:"not null"`
+	AcceptedAt  *time.Time    `json:"accepted_at"`
+	AcceptedBy  *uint         `json:"accepted_by"`
+	DeclinedAt  *time.Time    `json:"declined_at"`
+	RevokedAt   *time.Time    `json:"revoked_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+const (
+	DefaultInvitationExpiresInDays = 7
+	MaxInvitationExpiresInDays     = 30
+)
+
+// HouseholdInvitationRequest は POST /households/:id/invitations のリクエストボディ
+type HouseholdInvitationRequest struct {
+	Email         string        `json:"email"` // 指定するとそのアドレスに招待メールを送り、同じアドレスのユーザーだけが参加できる
+	Role          HouseholdRole `json:"role"`
+	ExpiresInDays int           `json:"expires_in_days"` // 省略時は 7 日
+}
+
+// InvitationTokenRequest は招待の承諾・辞退のリクエストボディ
+type InvitationTokenRequest struct {
+	Token string `json:"token"`
+}
+
+type HouseholdInvitationResponse struct {
+	ID            uint          `json:"id"`
+	HouseholdId   uint          `json:"household_id"`
+	HouseholdName string        `json:"household_name"`
+	InviterName   string        `json:"inviter_name"`
+	Email         string        `json:"email"`
+	Role          HouseholdRole `json:"role"`
+	ExpiresAt     time.Time     `json:"expires_at"`
+	CreatedAt     time.Time     `json:"created_at"`
+	// Token と URL は作成時のみ返す
+	Token string `json:"token,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
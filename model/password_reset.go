@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+)
+
+// PasswordReset はパスワード再設定用のワンタイムトークン。トークンはハッシュのみ保存する
+type PasswordReset struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserId    uint       `json:"user_id" gorm:"not null;index"`
+	User      User       `json:"user" gorm:"foreignKey:UserId"`
+	TokenHash string     `json:"-" gorm:"not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+const PasswordResetLifetime = time.Hour
+
+// ForgotPasswordRequest は POST /password/forgot のリクエストボディ
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest は POST /password/reset のリクエストボディ
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
@@ -7,18 +7,30 @@ import (
 )
 
 type Product struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	UserId      uint           `json:"user_id" gorm:"not null"`
-	User        User           `json:"user" gorm:"foreignKey:UserId"`
-	Name        string         `json:"name" gorm:"not null"`
-	Description string         `json:"description"`
-	Quantity    int            `json:"quantity" gorm:"default:1"`
-	ExpiryDate  time.Time      `json:"expiry_date" gorm:"not null"`
-	Type        ExpiryType     `json:"type" gorm:"not null"`
-	IsNotified  bool           `json:"is_notified" gorm:"default:false"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                   uint             `json:"id" gorm:"primaryKey"`
+	HouseholdId          uint             `json:"household_id" gorm:"not null;index"`
+	UserId               uint             `json:"user_id" gorm:"not null"` // 登録したユーザー
+	User                 User             `json:"user" gorm:"foreignKey:UserId"`
+	Name                 string           `json:"name" gorm:"not null"`
+	Description          string           `json:"description"`
+	Quantity             int              `json:"quantity" gorm:"default:1"`
+	ExpiryDate           time.Time        `json:"expiry_date" gorm:"not null"`
+	Type                 ExpiryType       `json:"type" gorm:"not null"`
+	IsNotified           bool             `json:"is_notified" gorm:"default:false"`
+	ArchivedAt           *time.Time       `json:"archived_at" gorm:"index"`
+	OpenedAt             *time.Time       `json:"opened_at"`
+	DaysAfterOpening     *int             `json:"days_after_opening"` // 開封後の消費目安（日数）
+	FrozenAt             *time.Time       `json:"frozen_at"`
+	ThawedAt             *time.Time       `json:"thawed_at"`
+	FreezerShelfLifeDays *int             `json:"freezer_shelf_life_days"`            // 冷凍した時点で決まる冷凍保存の日数
+	EffectiveExpiryDate  time.Time        `json:"effective_expiry_date" gorm:"index"` // 並び替え・絞り込み・通知に使う実際の期限
+	CategoryId           *uint            `json:"category_id" gorm:"index"`
+	Category             *Category        `json:"category,omitempty" gorm:"foreignKey:CategoryId;constraint:OnDelete:SET NULL"`
+	StorageLocationId    *uint            `json:"storage_location_id" gorm:"index"`
+	StorageLocation      *StorageLocation `json:"storage_location,omitempty" gorm:"foreignKey:StorageLocationId;constraint:OnDelete:SET NULL"`
+	CreatedAt            time.Time        `json:"created_at"`
+	UpdatedAt            time.Time        `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt   `json:"-" gorm:"index"`
 }
 
 type ExpiryType string
@@ -28,15 +40,90 @@ const (
 	ExpiryTypeUseBy      ExpiryType = "use_by"      // 消費期限
 )
 
+type ExpiryStatus string
+
+const (
+	ExpiryStatusFresh        ExpiryStatus = "fresh"
+	ExpiryStatusExpiringSoon ExpiryStatus = "expiring_soon"
+	ExpiryStatusExpired      ExpiryStatus = "expired"
+)
+
 type ProductResponse struct {
-	ID          uint       `json:"id"`
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	Quantity    int        `json:"quantity"`
-	ExpiryDate  time.Time  `json:"expiry_date"`
-	Type        ExpiryType `json:"type"`
-	IsNotified  bool       `json:"is_notified"`
-	DaysLeft    int        `json:"days_left"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID                   uint                     `json:"id"`
+	HouseholdId          uint                     `json:"household_id"`
+	Name                 string                   `json:"name"`
+	Description          string                   `json:"description"`
+	Quantity             int                      `json:"quantity"`
+	ExpiryDate           time.Time                `json:"expiry_date"`
+	EffectiveExpiryDate  time.Time                `json:"effective_expiry_date"`
+	Type                 ExpiryType               `json:"type"`
+	IsNotified           bool                     `json:"is_notified"`
+	DaysLeft             int                      `json:"days_left"`
+	Status               ExpiryStatus             `json:"status"`
+	ArchivedAt           *time.Time               `json:"archived_at"`
+	OpenedAt             *time.Time               `json:"opened_at"`
+	DaysAfterOpening     *int                     `json:"days_after_opening"`
+	FrozenAt             *time.Time               `json:"frozen_at"`
+	ThawedAt             *time.Time               `json:"thawed_at"`
+	FreezerShelfLifeDays *int                     `json:"freezer_shelf_life_days"`
+	Category             *CategoryResponse        `json:"category"`
+	StorageLocation      *StorageLocationResponse `json:"storage_location"`
+	CreatedAt            time.Time                `json:"created_at"`
+	UpdatedAt            time.Time                `json:"updated_at"`
+}
+
+// OpenProductRequest は POST /products/:id/open のリクエストボディ
+type OpenProductRequest struct {
+	OpenedAt         *time.Time `json:"opened_at"`          // 省略時は現在時刻
+	DaysAfterOpening *int       `json:"days_after_opening"` // 省略時は製品に設定済みの値
+}
+
+// FreezeProductRequest は POST /products/:id/freeze のリクエストボディ
+type FreezeProductRequest struct {
+	FrozenAt             *time.Time `json:"frozen_at"`               // 省略時は現在時刻
+	StorageLocationId    *uint      `json:"storage_location_id"`     // 省略時は最初の冷凍庫
+	FreezerShelfLifeDays *int       `json:"freezer_shelf_life_days"` // 省略時はカテゴリの設定、なければ既定値
+}
+
+// ThawProductRequest は POST /products/:id/thaw のリクエストボディ
+type ThawProductRequest struct {
+	ThawedAt          *time.Time `json:"thawed_at"`           // 省略時は現在時刻
+	StorageLocationId *uint      `json:"storage_location_id"` // 省略時は最初の冷蔵庫、なければ未設定
+}
+
+// ProductQuery は GET /products のクエリパラメータ
+type ProductQuery struct {
+	ExpiryFrom        string       `query:"expiry_from" json:"expiry_from"` // YYYY-MM-DD（この日を含む）
+	ExpiryTo          string       `query:"expiry_to" json:"expiry_to"`     // YYYY-MM-DD（この日を含む）
+	Type              ExpiryType   `query:"type" json:"type"`
+	Status            ExpiryStatus `query:"status" json:"status"`
+	Q                 string       `query:"q" json:"q"`
+	Archived          bool         `query:"archived" json:"archived"` // true: 使い切った製品のみ
+	CategoryId        uint         `query:"category_id" json:"category_id"`
+	StorageLocationId uint         `query:"storage_location_id" json:"storage_location_id"`
+	Sort              string       `query:"sort" json:"sort"`
+	Order             string       `query:"order" json:"order"`
+	Page              int          `query:"page" json:"page"`
+	PerPage           int          `query:"per_page" json:"per_page"`
+}
+
+const (
+	ProductSortCreatedAt  = "created_at"
+	ProductSortExpiryDate = "expiry_date"
+	ProductSortName       = "name"
+	ProductSortQuantity   = "quantity"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+
+	DefaultProductPerPage = 50
+	MaxProductPerPage     = 100
+)
+
+type ProductListResponse struct {
+	Items      []ProductResponse `json:"items"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	PerPage    int               `json:"per_page"`
+	TotalPages int               `json:"total_pages"`
 }
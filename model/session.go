@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+)
+
+// Session はログイン中の端末ごとの状態。リフレッシュトークンはハッシュのみ保存する
+type Session struct {
+	ID               uint       `json:"id" gorm:"primaryKey"`
+	UserId           uint       `json:"user_id" gorm:"not null;index"`
+	User             User       `json:"user" gorm:"foreignKey:UserId"`
+	RefreshTokenHash string     `json:"-" gorm:"not null"`
+	UserAgent        string     `json:"user_agent"`
+	IPAddress        string     `json:"ip_address"`
+	LastUsedAt       time.Time  `json:"last_used_at"`
+	ExpiresAt        time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+const (
+	AccessTokenLifetime  = 15 * time.Minute
+	RefreshTokenLifetime = 30 * 24 * time.Hour // 最後にリフレッシュしてからの有効期間
+)
+
+// SessionClient はセッションを開始・更新した端末の情報
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
+
+// AuthTokens はログイン・リフレッシュで発行するトークンの組
+type AuthTokens struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+type SessionResponse struct {
+	ID         uint      `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	Current    bool      `json:"current"` // このリクエストのセッションかどうか
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
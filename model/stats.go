@@ -0,0 +1,58 @@
+package model
+
+const (
+	StatsPeriodWeek  = "week"
+	StatsPeriodMonth = "month"
+)
+
+// StatsQuery は GET /stats/expired のクエリパラメータ
+type StatsQuery struct {
+	Period string `query:"period" json:"period"` // week（既定） / month
+	From   string `query:"from" json:"from"`     // YYYY-MM-DD（省略時は直近12期間）
+}
+
+type ExpiredStatsBucket struct {
+	PeriodStart string `json:"period_start"` // 週（月曜始まり）または月の初日 YYYY-MM-DD
+	Products    int64  `json:"products"`
+	Quantity    int64  `json:"quantity"`
+}
+
+type ExpiredStatsResponse struct {
+	Period  string               `json:"period"`
+	Buckets []ExpiredStatsBucket `json:"buckets"`
+}
+
+type WasteStats struct {
+	Consumed  int64   `json:"consumed"`
+	Wasted    int64   `json:"wasted"`
+	WasteRate float64 `json:"waste_rate"`
+}
+
+type WasteByTypeStats struct {
+	Type ExpiryType `json:"type"`
+	WasteStats
+}
+
+// WasteByCategoryStats の CategoryId が nil の行はカテゴリ未設定の製品
+type WasteByCategoryStats struct {
+	CategoryId   *uint  `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	WasteStats
+}
+
+type WasteStatsResponse struct {
+	Total      WasteStats             `json:"total"`
+	ByType     []WasteByTypeStats     `json:"by_type"`
+	ByCategory []WasteByCategoryStats `json:"by_category"`
+}
+
+type ConsumptionTimeStats struct {
+	AverageDays  float64 `json:"average_days"`
+	Consumptions int64   `json:"consumptions"`
+}
+
+type UpcomingExpiryStats struct {
+	Expired      int64 `json:"expired"`
+	Within7Days  int64 `json:"within_7_days"`
+	Within30Days int64 `json:"within_30_days"`
+}
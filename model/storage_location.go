@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type StorageLocationKind string
+
+const (
+	StorageLocationKindFridge  StorageLocationKind = "fridge"  // 冷蔵
+	StorageLocationKindFreezer StorageLocationKind = "freezer" // 冷凍
+	StorageLocationKindPantry  StorageLocationKind = "pantry"  // 常温
+	StorageLocationKindOther   StorageLocationKind = "other"
+)
+
+type StorageLocation struct {
+	ID          uint                `json:"id" gorm:"primaryKey"`
+	HouseholdId uint                `json:"household_id" gorm:"not null;index"`
+	UserId      uint                `json:"user_id" gorm:"not null;index"` // 作成したユーザー
+	User        User                `json:"user" gorm:"foreignKey:UserId"`
+	Name        string              `json:"name" gorm:"not null"`
+	Kind        StorageLocationKind `json:"kind" gorm:"not null"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt      `json:"-" gorm:"index"`
+}
+
+type StorageLocationResponse struct {
+	ID        uint                `json:"id"`
+	Name      string              `json:"name"`
+	Kind      StorageLocationKind `json:"kind"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+)
+
+// RecoveryCode は認証アプリを使えないときのための使い捨てコード。ハッシュのみ保存する
+type RecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserId    uint       `json:"user_id" gorm:"not null;index"`
+	CodeHash  string     `json:"-" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+const (
+	RecoveryCodeCount          = 10
+	TwoFactorChallengeLifetime = 5 * time.Minute
+)
+
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"` // QR コードにして認証アプリで読み取る
+}
+
+// TwoFactorCodeRequest は POST /2fa/confirm のリクエストボディ
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"` // この応答でしか取得できない
+}
+
+// TwoFactorDisableRequest は POST /2fa/disable のリクエストボディ
+type TwoFactorDisableRequest struct {
+	CurrentPassword string `json:"current_password"`
+	Code            string `json:"code"` // 認証アプリのコードまたはリカバリーコード
+}
+
+// TwoFactorLoginRequest は POST /login/2fa のリクエストボディ
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"` // 認証アプリのコードまたはリカバリーコード
+}
+
+// LoginResult はパスワードによるログインの結果。二要素認証が有効な場合は
+// トークンの代わりに ChallengeToken を返す
+type LoginResult struct {
+	Tokens         AuthTokens
+	ChallengeToken string
+}
+
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+}
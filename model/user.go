@@ -7,17 +7,64 @@ import (
 )
 
 type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
-	Password  string         `json:"password"`
-	Name      string         `json:"name" gorm:"not null"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	Email           string         `json:"email" gorm:"uniqueIndex:idx_users_email_active,where:deleted_at IS NULL;not null"`
+	Password        string         `json:"password"`
+	Name            string         `json:"name" gorm:"not null"`
+	TimeZone        string         `json:"time_zone" gorm:"not null;default:'Asia/Tokyo'"`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at"`            // 未確認のアドレスには通知を送らない
+	TotpSecret      string         `json:"-" gorm:"not null;default:''"` // 暗号化した TOTP の秘密鍵（登録中または有効な場合）
+	TotpEnabledAt   *time.Time     `json:"-"`                            // 二要素認証を有効にした日時
+	TotpLastStep    int64          `json:"-" gorm:"not null;default:0"`  // 最後に受け付けたコードの時間帯（再利用を防ぐ）
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+const DefaultTimeZone = "Asia/Tokyo"
+
+// Location returns the user's time zone, in which their calendar dates are
+// counted.
+func (u User) Location() *time.Location {
+	name := u.TimeZone
+	if name == "" {
+		name = DefaultTimeZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
 }
 
 type UserResponse struct {
-	ID    uint   `json:"id"`
-	Email string `json:"email"`
-	Name  string `json:"name"`
+	ID               uint       `json:"id"`
+	Email            string     `json:"email"`
+	Name             string     `json:"name"`
+	TimeZone         string     `json:"time_zone"`
+	EmailVerifiedAt  *time.Time `json:"email_verified_at"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+}
+
+// UpdateProfileRequest は PUT /me のリクエストボディ
+type UpdateProfileRequest struct {
+	Name     string `json:"name"`
+	TimeZone string `json:"time_zone"`
+}
+
+// ChangeEmailRequest は PUT /me/email のリクエストボディ
+type ChangeEmailRequest struct {
+	Email           string `json:"email"`
+	CurrentPassword string `json:"current_password"`
+}
+
+// ChangePasswordRequest は PUT /me/password のリクエストボディ
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// DeleteAccountRequest は DELETE /me のリクエストボディ
+type DeleteAccountRequest struct {
+	CurrentPassword string `json:"current_password"`
 }
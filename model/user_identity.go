@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// UserIdentity は外部の ID プロバイダー（OpenID Connect）のアカウントとユーザーの紐付け
+type UserIdentity struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserId    uint      `json:"user_id" gorm:"not null;index"`
+	User      User      `json:"user" gorm:"foreignKey:UserId"`
+	Issuer    string    `json:"issuer" gorm:"not null;uniqueIndex:idx_user_identities_subject"`
+	Subject   string    `json:"subject" gorm:"not null;uniqueIndex:idx_user_identities_subject"`
+	Email     string    `json:"email"` // 紐付けたときの ID プロバイダー側のアドレス
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OidcStateLifetime は ID プロバイダーでのログインを完了するまでの猶予
+const OidcStateLifetime = 10 * time.Minute
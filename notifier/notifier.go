@@ -0,0 +1,24 @@
+package notifier
+
+import (
+	"expiry_tracker/model"
+	"log"
+)
+
+type INotifier interface {
+	NotifyExpiringProducts(user model.User, products []model.Product) error
+}
+
+type logNotifier struct{}
+
+func NewLogNotifier() INotifier {
+	return &logNotifier{}
+}
+
+func (ln *logNotifier) NotifyExpiringProducts(user model.User, products []model.Product) error {
+	for _, p := range products {
+		log.Printf("notify user=%d email=%s product=%d name=%q expiry_date=%s",
+			user.ID, user.Email, p.ID, p.Name, p.EffectiveExpiryDate.Format("2006-01-02"))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// activeApiToken matches tokens that have not expired.
+const activeApiToken = "expires_at IS NULL OR expires_at > ?"
+
+type IApiTokenRepository interface {
+	CreateApiToken(token *model.ApiToken) error
+	CountApiTokens(userId uint) (int64, error)
+	GetApiTokensByUserId(tokens *[]model.ApiToken, userId uint) error
+	GetActiveApiTokenByHash(token *model.ApiToken, tokenHash string, now time.Time) error
+	TouchApiToken(tokenId uint, now time.Time) error
+	DeleteApiToken(userId uint, tokenId uint) error
+}
+
+type apiTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewApiTokenRepository(db *gorm.DB) IApiTokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (ar *apiTokenRepository) CreateApiToken(token *model.ApiToken) error {
+	if err := ar.db.Omit(clause.Associations).Create(token).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ar *apiTokenRepository) CountApiTokens(userId uint) (int64, error) {
+	var count int64
+	if err := ar.db.Model(&model.ApiToken{}).Where("user_id = ?", userId).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (ar *apiTokenRepository) GetApiTokensByUserId(tokens *[]model.ApiToken, userId uint) error {
+	if err := ar.db.Where("user_id = ?", userId).Order("created_at, id").Find(tokens).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ar *apiTokenRepository) GetActiveApiTokenByHash(token *model.ApiToken, tokenHash string, now time.Time) error {
+	if err := ar.db.Where("token_hash = ?", tokenHash).Where(activeApiToken, now).First(token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("api token not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// TouchApiToken records that the token was used. To avoid a write on every
+// request, last_used_at is only moved forward once per
+// model.ApiTokenTouchInterval.
+func (ar *apiTokenRepository) TouchApiToken(tokenId uint, now time.Time) error {
+	if err := ar.db.Model(&model.ApiToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", tokenId, now.Add(-model.ApiTokenTouchInterval)).
+		Update("last_used_at", now).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ar *apiTokenRepository) DeleteApiToken(userId uint, tokenId uint) error {
+	result := ar.db.Where("user_id = ? AND id = ?", userId, tokenId).Delete(&model.ApiToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("api token not found")
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"errors"
+	"expiry_tracker/model"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IAttemptCounterRepository stores attempt counters. Get leaves counter at its
+// zero value when key has no attempts.
+type IAttemptCounterRepository interface {
+	GetCounter(counter *model.AttemptCounter, key string) error
+	IncrementCounter(counter *model.AttemptCounter, key string, now time.Time, window time.Duration) error
+	DeleteCounter(key string) error
+}
+
+type attemptCounterRepository struct {
+	db        *gorm.DB
+	retention time.Duration
+	mu        sync.Mutex
+	prunedAt  time.Time
+}
+
+// NewAttemptCounterRepository returns a store backed by Postgres, shared by
+// all replicas of the API. Counters whose last attempt is older than
+// retention are deleted while counting attempts.
+func NewAttemptCounterRepository(db *gorm.DB, retention time.Duration) IAttemptCounterRepository {
+	return &attemptCounterRepository{db: db, retention: retention}
+}
+
+func (ar *attemptCounterRepository) GetCounter(counter *model.AttemptCounter, key string) error {
+	*counter = model.AttemptCounter{}
+	if err := ar.db.Where("key = ?", key).First(counter).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			*counter = model.AttemptCounter{Key: key}
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// IncrementCounter counts an attempt at now in a single upsert. The count
+// starts over when the previous attempt is older than window.
+func (ar *attemptCounterRepository) IncrementCounter(counter *model.AttemptCounter, key string, now time.Time, window time.Duration) error {
+	*counter = model.AttemptCounter{Key: key, Count: 1, LastAttemptAt: now}
+	if err := ar.db.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"count": gorm.Expr("CASE WHEN attempt_counters.last_attempt_at > ? THEN attempt_counters.count + 1 ELSE 1 END",
+					now.Add(-window)),
+				"last_attempt_at": now,
+			}),
+		},
+		clause.Returning{},
+	).Create(counter).Error; err != nil {
+		return err
+	}
+	return ar.deleteExpired(now)
+}
+
+// deleteExpired deletes the counters older than the retention, at most once
+// per retention in each instance.
+func (ar *attemptCounterRepository) deleteExpired(now time.Time) error {
+	ar.mu.Lock()
+	if now.Sub(ar.prunedAt) < ar.retention {
+		ar.mu.Unlock()
+		return nil
+	}
+	ar.prunedAt = now
+	ar.mu.Unlock()
+
+	if err := ar.db.Where("last_attempt_at < ?", now.Add(-ar.retention)).Delete(&model.AttemptCounter{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ar *attemptCounterRepository) DeleteCounter(key string) error {
+	if err := ar.db.Where("key = ?", key).Delete(&model.AttemptCounter{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ICategoryRepository interface {
+	GetAllCategories(categories *[]model.Category, householdId uint) error
+	GetCategoryById(category *model.Category, householdId uint, categoryId uint) error
+	CreateCategory(category *model.Category) error
+	UpdateCategory(category *model.Category, householdId uint, categoryId uint) error
+	DeleteCategory(householdId uint, categoryId uint) error
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) ICategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (cr *categoryRepository) GetAllCategories(categories *[]model.Category, householdId uint) error {
+	if err := cr.db.Where("household_id = ?", householdId).Order("name, id").Find(categories).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cr *categoryRepository) GetCategoryById(category *model.Category, householdId uint, categoryId uint) error {
+	if err := cr.db.Where("household_id = ?", householdId).First(category, categoryId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("category not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (cr *categoryRepository) CreateCategory(category *model.Category) error {
+	if err := cr.db.Create(category).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cr *categoryRepository) UpdateCategory(category *model.Category, householdId uint, categoryId uint) error {
+	result := cr.db.Model(category).Clauses(clause.Returning{}).Where("household_id = ? AND id = ?", householdId, categoryId).
+		Updates(map[string]interface{}{"name": category.Name, "freezer_shelf_life_days": category.FreezerShelfLifeDays})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("category not found")
+	}
+	return nil
+}
+
+// DeleteCategory soft-deletes the category and detaches it from all products.
+func (cr *categoryRepository) DeleteCategory(householdId uint, categoryId uint) error {
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("household_id = ? AND id = ?", householdId, categoryId).Delete(&model.Category{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFound("category not found")
+		}
+		if err := tx.Model(&model.Product{}).Where("category_id = ?", categoryId).Update("category_id", nil).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
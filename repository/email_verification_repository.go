@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IEmailVerificationRepository interface {
+	CreateEmailVerification(verification *model.EmailVerification) error
+	GetEmailVerificationById(verification *model.EmailVerification, verificationId uint) error
+	CountEmailVerificationsSince(count *int64, userId uint, since time.Time) error
+	VerifyEmail(verificationId uint, now time.Time) error
+}
+
+type emailVerificationRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailVerificationRepository(db *gorm.DB) IEmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+func (er *emailVerificationRepository) CreateEmailVerification(verification *model.EmailVerification) error {
+	if err := er.db.Omit(clause.Associations).Create(verification).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (er *emailVerificationRepository) GetEmailVerificationById(verification *model.EmailVerification, verificationId uint) error {
+	if err := er.db.First(verification, verificationId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("email verification not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (er *emailVerificationRepository) CountEmailVerificationsSince(count *int64, userId uint, since time.Time) error {
+	if err := er.db.Model(&model.EmailVerification{}).Where("user_id = ? AND created_at > ?", userId, since).
+		Count(count).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// VerifyEmail uses the verification token and marks the user's email address
+// as verified in one transaction. The address must still be the one the
+// token was sent to.
+func (er *emailVerificationRepository) VerifyEmail(verificationId uint, now time.Time) error {
+	return er.db.Transaction(func(tx *gorm.DB) error {
+		verification := model.EmailVerification{}
+		result := tx.Model(&verification).Clauses(clause.Returning{}).Where("id = ?", verificationId).
+			Where("used_at IS NULL AND expires_at > ?", now).Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.Invalid("invalid or expired token")
+		}
+
+		result = tx.Model(&model.User{}).Where("id = ? AND email = ?", verification.UserId, verification.Email).
+			Update("email_verified_at", gorm.Expr("COALESCE(email_verified_at, ?)", now))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.Invalid("invalid or expired token")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"errors"
+	"expiry_tracker/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IExpiryNotificationRepository interface {
+	ClaimNotification(notification *model.ExpiryNotification, now time.Time, claimTimeout time.Duration) (bool, bool, error)
+	MarkSent(ids []uint, now time.Time) error
+	ReleaseNotifications(ids []uint) error
+}
+
+type expiryNotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewExpiryNotificationRepository(db *gorm.DB) IExpiryNotificationRepository {
+	return &expiryNotificationRepository{db: db}
+}
+
+// ClaimNotification reserves the right to send notification, keyed by its
+// product, user and expiry date, in a single upsert. A claim that was never
+// marked sent is taken over once it is older than claimTimeout, so a crash
+// between claiming and sending delays the notification instead of losing it.
+// It reports whether the caller won the claim and, if not, whether the
+// notification has already been sent.
+func (nr *expiryNotificationRepository) ClaimNotification(notification *model.ExpiryNotification, now time.Time, claimTimeout time.Duration) (bool, bool, error) {
+	notification.ClaimedAt = now
+	result := nr.db.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "product_id"}, {Name: "user_id"}, {Name: "expiry_date"}},
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "expiry_notifications.sent_at IS NULL AND expiry_notifications.claimed_at < ?", Vars: []interface{}{now.Add(-claimTimeout)}},
+			}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"claimed_at": now}),
+		},
+		clause.Returning{},
+	).Create(notification)
+	if result.Error != nil {
+		return false, false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, false, nil
+	}
+
+	existing := model.ExpiryNotification{}
+	if err := nr.db.Where("product_id = ? AND user_id = ? AND expiry_date = ?",
+		notification.ProductId, notification.UserId, notification.ExpiryDate).First(&existing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	return false, existing.SentAt != nil, nil
+}
+
+func (nr *expiryNotificationRepository) MarkSent(ids []uint, now time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := nr.db.Model(&model.ExpiryNotification{}).Where("id IN ?", ids).Update("sent_at", now).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReleaseNotifications drops claims whose send failed, so that the next run
+// retries exactly those recipients.
+func (nr *expiryNotificationRepository) ReleaseNotifications(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := nr.db.Where("id IN ? AND sent_at IS NULL", ids).Delete(&model.ExpiryNotification{}).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"expiry_tracker/migrations"
+
+	"gorm.io/gorm"
+)
+
+type IHealthRepository interface {
+	Ping(ctx context.Context) error
+	SchemaVersion(ctx context.Context) (int64, error)
+}
+
+type healthRepository struct {
+	db         *gorm.DB
+	migrations []migrations.Migration
+}
+
+// NewHealthRepository checks the schema against the given migrations, which
+// are the ones embedded in the running binary.
+func NewHealthRepository(db *gorm.DB, ms []migrations.Migration) IHealthRepository {
+	return &healthRepository{db: db, migrations: ms}
+}
+
+func (hr *healthRepository) Ping(ctx context.Context) error {
+	sqlDB, err := hr.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// SchemaVersion returns the latest applied migration, with an error unless
+// the schema matches the migrations exactly.
+func (hr *healthRepository) SchemaVersion(ctx context.Context) (int64, error) {
+	sqlDB, err := hr.db.DB()
+	if err != nil {
+		return 0, err
+	}
+	return migrations.NewRunner(sqlDB, hr.migrations).Current(ctx)
+}
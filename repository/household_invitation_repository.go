@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// pendingInvitation matches invitations that can still be accepted.
+const pendingInvitation = "accepted_at IS NULL AND declined_at IS NULL AND revoked_at IS NULL AND expires_at > ?"
+
+type IHouseholdInvitationRepository interface {
+	CreateInvitation(invitation *model.HouseholdInvitation) error
+	GetInvitationById(invitation *model.HouseholdInvitation, invitationId uint) error
+	GetPendingInvitations(invitations *[]model.HouseholdInvitation, householdId uint, now time.Time) error
+	RevokeInvitation(householdId uint, invitationId uint, now time.Time) error
+	AcceptInvitation(invitationId uint, userId uint, now time.Time) error
+	DeclineInvitation(invitationId uint, now time.Time) error
+}
+
+type householdInvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewHouseholdInvitationRepository(db *gorm.DB) IHouseholdInvitationRepository {
+	return &householdInvitationRepository{db: db}
+}
+
+func (ir *householdInvitationRepository) CreateInvitation(invitation *model.HouseholdInvitation) error {
+	if err := ir.db.Omit(clause.Associations).Create(invitation).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ir *householdInvitationRepository) GetInvitationById(invitation *model.HouseholdInvitation, invitationId uint) error {
+	if err := ir.db.Joins("Household").Joins("Inviter").First(invitation, invitationId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("invitation not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (ir *householdInvitationRepository) GetPendingInvitations(invitations *[]model.HouseholdInvitation, householdId uint, now time.Time) error {
+	if err := ir.db.Joins("Household").Joins("Inviter").
+		Where("household_invitations.household_id = ?", householdId).
+		Where(pendingInvitation, now).
+		Order("household_invitations.created_at, household_invitations.id").Find(invitations).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ir *householdInvitationRepository) RevokeInvitation(householdId uint, invitationId uint, now time.Time) error {
+	result := ir.db.Model(&model.HouseholdInvitation{}).Where("household_id = ? AND id = ?", householdId, invitationId).
+		Where(pendingInvitation, now).Update("revoked_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("invitation not found")
+	}
+	return nil
+}
+
+// AcceptInvitation marks the invitation as used and adds userId to the
+// household in one transaction. The update is conditional on the invitation
+// still being pending, so a token can be used only once.
+func (ir *householdInvitationRepository) AcceptInvitation(invitationId uint, userId uint, now time.Time) error {
+	return ir.db.Transaction(func(tx *gorm.DB) error {
+		invitation := model.HouseholdInvitation{}
+		result := tx.Model(&invitation).Clauses(clause.Returning{}).Where("id = ?", invitationId).Where(pendingInvitation, now).
+			Updates(map[string]interface{}{"accepted_at": now, "accepted_by": userId})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.Conflict("invitation is no longer valid")
+		}
+
+		member := model.HouseholdMember{HouseholdId: invitation.HouseholdId, UserId: userId, Role: invitation.Role}
+		if err := tx.Omit(clause.Associations).Create(&member).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return apperror.Conflict("already a member of the household")
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+func (ir *householdInvitationRepository) DeclineInvitation(invitationId uint, now time.Time) error {
+	result := ir.db.Model(&model.HouseholdInvitation{}).Where("id = ?", invitationId).Where(pendingInvitation, now).Update("declined_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.Conflict("invitation is no longer valid")
+	}
+	return nil
+}
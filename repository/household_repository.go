@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IHouseholdRepository interface {
+	GetMembershipsByUserId(members *[]model.HouseholdMember, userId uint) error
+	GetMember(member *model.HouseholdMember, householdId uint, userId uint) error
+	GetMembers(members *[]model.HouseholdMember, householdId uint) error
+	CreateHousehold(household *model.Household, ownerId uint) error
+	UpdateHousehold(household *model.Household, householdId uint) error
+	DeleteHousehold(householdId uint) error
+	UpdateMemberRole(householdId uint, userId uint, role model.HouseholdRole) error
+	DeleteMember(householdId uint, userId uint) error
+}
+
+type householdRepository struct {
+	db *gorm.DB
+}
+
+func NewHouseholdRepository(db *gorm.DB) IHouseholdRepository {
+	return &householdRepository{db: db}
+}
+
+// GetMembershipsByUserId returns the user's memberships with their households,
+// oldest first. The first one is the user's default household.
+func (hr *householdRepository) GetMembershipsByUserId(members *[]model.HouseholdMember, userId uint) error {
+	if err := hr.db.Joins("Household").Where("household_members.user_id = ?", userId).
+		Order("household_members.created_at, household_members.id").Find(members).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (hr *householdRepository) GetMember(member *model.HouseholdMember, householdId uint, userId uint) error {
+	if err := hr.db.Joins("Household").Where("household_members.household_id = ? AND household_members.user_id = ?", householdId, userId).
+		First(member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("household not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (hr *householdRepository) GetMembers(members *[]model.HouseholdMember, householdId uint) error {
+	if err := hr.db.Joins("User").Where("household_members.household_id = ?", householdId).
+		Order("household_members.created_at, household_members.id").Find(members).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateHousehold creates the household together with ownerId as its owner.
+func (hr *householdRepository) CreateHousehold(household *model.Household, ownerId uint) error {
+	return hr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(household).Error; err != nil {
+			return err
+		}
+		member := model.HouseholdMember{HouseholdId: household.ID, UserId: ownerId, Role: model.HouseholdRoleOwner}
+		if err := tx.Omit(clause.Associations).Create(&member).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func (hr *householdRepository) UpdateHousehold(household *model.Household, householdId uint) error {
+	result := hr.db.Model(household).Clauses(clause.Returning{}).Where("id = ?", householdId).Update("name", household.Name)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("household not found")
+	}
+	return nil
+}
+
+// DeleteHousehold soft-deletes the household with its products, categories and
+// storage locations, and removes all memberships.
+func (hr *householdRepository) DeleteHousehold(householdId uint) error {
+	return hr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&model.Household{}, householdId)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFound("household not found")
+		}
+		return deleteHouseholdData(tx, householdId)
+	})
+}
+
+// deleteHouseholdData deletes everything that belongs to householdId: its
+// products, categories, storage locations and memberships.
+func deleteHouseholdData(tx *gorm.DB, householdId uint) error {
+	for _, v := range []interface{}{&model.Product{}, &model.Category{}, &model.StorageLocation{}, &model.HouseholdMember{}} {
+		if err := tx.Where("household_id = ?", householdId).Delete(v).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (hr *householdRepository) UpdateMemberRole(householdId uint, userId uint, role model.HouseholdRole) error {
+	return hr.changeMembers(householdId, func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&model.HouseholdMember{}).Where("household_id = ? AND user_id = ?", householdId, userId).Update("role", role)
+	})
+}
+
+func (hr *householdRepository) DeleteMember(householdId uint, userId uint) error {
+	return hr.changeMembers(householdId, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("household_id = ? AND user_id = ?", householdId, userId).Delete(&model.HouseholdMember{})
+	})
+}
+
+// changeMembers applies change while holding a lock on the household, and
+// rolls it back if the household would be left without an owner.
+func (hr *householdRepository) changeMembers(householdId uint, change func(tx *gorm.DB) *gorm.DB) error {
+	return hr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model.Household{}, householdId).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.NotFound("household not found")
+			}
+			return err
+		}
+		result := change(tx)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFound("member not found")
+		}
+
+		var owners int64
+		if err := tx.Model(&model.HouseholdMember{}).Where("household_id = ? AND role = ?", householdId, model.HouseholdRoleOwner).
+			Count(&owners).Error; err != nil {
+			return err
+		}
+		if owners == 0 {
+			return apperror.Conflict("household must have at least one owner")
+		}
+		return nil
+	})
+}
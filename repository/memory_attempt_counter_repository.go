@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"expiry_tracker/model"
+	"sync"
+	"time"
+)
+
+// maxMemoryCounters bounds the in-memory store; above it, counters whose last
+// attempt is older than the window are dropped.
+const maxMemoryCounters = 10000
+
+type memoryAttemptCounterRepository struct {
+	mu       sync.Mutex
+	counters map[string]model.AttemptCounter
+}
+
+// NewMemoryAttemptCounterRepository returns a store that keeps counters in
+// process memory. It is suitable for a single instance and for tests;
+// counters are lost on restart.
+func NewMemoryAttemptCounterRepository() IAttemptCounterRepository {
+	return &memoryAttemptCounterRepository{counters: map[string]model.AttemptCounter{}}
+}
+
+func (mr *memoryAttemptCounterRepository) GetCounter(counter *model.AttemptCounter, key string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	c, ok := mr.counters[key]
+	if !ok {
+		c = model.AttemptCounter{Key: key}
+	}
+	*counter = c
+	return nil
+}
+
+func (mr *memoryAttemptCounterRepository) IncrementCounter(counter *model.AttemptCounter, key string, now time.Time, window time.Duration) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	if len(mr.counters) >= maxMemoryCounters {
+		for k, v := range mr.counters {
+			if !v.LastAttemptAt.After(now.Add(-window)) {
+				delete(mr.counters, k)
+			}
+		}
+	}
+
+	c, ok := mr.counters[key]
+	if !ok || !c.LastAttemptAt.After(now.Add(-window)) {
+		c = model.AttemptCounter{Key: key}
+	}
+	c.Count++
+	c.LastAttemptAt = now
+	mr.counters[key] = c
+	*counter = c
+	return nil
+}
+
+func (mr *memoryAttemptCounterRepository) DeleteCounter(key string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	delete(mr.counters, key)
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// pendingPasswordReset matches reset tokens that can still be used.
+const pendingPasswordReset = "used_at IS NULL AND expires_at > ?"
+
+type IPasswordResetRepository interface {
+	CreatePasswordReset(reset *model.PasswordReset) error
+	GetPasswordResetById(reset *model.PasswordReset, resetId uint) error
+	ResetPassword(resetId uint, passwordHash string, now time.Time) error
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) IPasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (pr *passwordResetRepository) CreatePasswordReset(reset *model.PasswordReset) error {
+	if err := pr.db.Omit(clause.Associations).Create(reset).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *passwordResetRepository) GetPasswordResetById(reset *model.PasswordReset, resetId uint) error {
+	if err := pr.db.First(reset, resetId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("password reset not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// ResetPassword uses the reset token and sets the user's password in one
+// transaction. The user's other pending reset tokens are invalidated and all
+// of their sessions are revoked, so a leaked token or session cannot outlive
+// the reset.
+func (pr *passwordResetRepository) ResetPassword(resetId uint, passwordHash string, now time.Time) error {
+	return pr.db.Transaction(func(tx *gorm.DB) error {
+		reset := model.PasswordReset{}
+		result := tx.Model(&reset).Clauses(clause.Returning{}).Where("id = ?", resetId).Where(pendingPasswordReset, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.Invalid("invalid or expired token")
+		}
+
+		if err := tx.Model(&model.User{}).Where("id = ?", reset.UserId).Update("password", passwordHash).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.PasswordReset{}).Where("user_id = ?", reset.UserId).Where(pendingPasswordReset, now).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Session{}).Where("user_id = ?", reset.UserId).Where(activeSession, now).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
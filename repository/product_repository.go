@@ -2,19 +2,58 @@ package repository
 
 import (
 	"errors"
+	"expiry_tracker/apperror"
 	"expiry_tracker/model"
-	"fmt"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// ExpiryRange matches products of Type whose expiry date is in [From, Before).
+// A nil bound is open.
+type ExpiryRange struct {
+	Type   model.ExpiryType
+	From   *time.Time
+	Before *time.Time
+}
+
+type ProductFilter struct {
+	ExpiryFrom   *time.Time
+	ExpiryBefore *time.Time
+	Type         model.ExpiryType
+	// ExpiryRanges are OR-ed together; used to express an expiry status whose
+	// thresholds depend on the expiry type.
+	ExpiryRanges      []ExpiryRange
+	Name              string
+	Archived          bool
+	CategoryId        uint
+	StorageLocationId uint
+	Sort              string
+	Desc              bool
+	Limit             int
+	Offset            int
+}
+
+var productSortColumns = map[string]string{
+	model.ProductSortCreatedAt:  "products.created_at",
+	model.ProductSortExpiryDate: "products.effective_expiry_date",
+	model.ProductSortName:       "products.name",
+	model.ProductSortQuantity:   "products.quantity",
+}
+
 type IProductRepository interface {
-	GetAllProducts(products *[]model.Product, userId uint) error
-	GetProductById(product *model.Product, userId uint, productId uint) error
+	GetAllProducts(products *[]model.Product, total *int64, householdId uint, filter ProductFilter) error
+	GetProductById(product *model.Product, householdId uint, productId uint) error
 	CreateProduct(product *model.Product) error
-	UpdateProduct(product *model.Product, userId uint, productId uint) error
-	DeleteProduct(userId uint, productId uint) error
+	UpdateProduct(product *model.Product, householdId uint, productId uint) error
+	UpdateProductFields(product *model.Product, householdId uint, productId uint, columns ...string) error
+	DeleteProduct(householdId uint, productId uint) error
+	DecrementQuantity(product *model.Product, event *model.ConsumptionEvent, householdId uint, productId uint) error
+	GetConsumptionEvents(events *[]model.ConsumptionEvent, householdId uint, productId uint) error
+	GetNotificationCandidates(products *[]model.Product, until time.Time) error
+	MarkNotified(productId uint, expiryDate time.Time) error
 }
 
 type productRepository struct {
@@ -25,45 +64,185 @@ func NewProductRepository(db *gorm.DB) IProductRepository {
 	return &productRepository{db: db}
 }
 
-func (pr *productRepository) GetAllProducts(products *[]model.Product, userId uint) error {
-	if err := pr.db.Joins("User").Where("user_id = ?", userId).Order("created_at").Find(products).Error; err != nil {
+func (pr *productRepository) GetAllProducts(products *[]model.Product, total *int64, householdId uint, filter ProductFilter) error {
+	if err := pr.db.Model(&model.Product{}).Scopes(productFilterScope(householdId, filter)).Count(total).Error; err != nil {
+		return err
+	}
+
+	column, ok := productSortColumns[filter.Sort]
+	if !ok {
+		column = productSortColumns[model.ProductSortCreatedAt]
+	}
+	order := clause.OrderBy{Columns: []clause.OrderByColumn{
+		{Column: clause.Column{Name: column, Raw: true}, Desc: filter.Desc},
+		{Column: clause.Column{Name: "products.id", Raw: true}, Desc: filter.Desc},
+	}}
+	query := pr.db.Joins("User").Joins("Category").Joins("StorageLocation").Scopes(productFilterScope(householdId, filter)).Order(order)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+	if err := query.Find(products).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
-func (pr *productRepository) GetProductById(product *model.Product, userId uint, productId uint) error {
-	if err := pr.db.Joins("User").Where("user_id = ?", userId).First(product, productId).Error; err != nil {
+func productFilterScope(householdId uint, filter ProductFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("products.household_id = ?", householdId)
+		if filter.Archived {
+			db = db.Where("products.archived_at IS NOT NULL")
+		} else {
+			db = db.Where("products.archived_at IS NULL")
+		}
+		if filter.ExpiryFrom != nil {
+			db = db.Where("products.effective_expiry_date >= ?", *filter.ExpiryFrom)
+		}
+		if filter.ExpiryBefore != nil {
+			db = db.Where("products.effective_expiry_date < ?", *filter.ExpiryBefore)
+		}
+		if filter.CategoryId != 0 {
+			db = db.Where("products.category_id = ?", filter.CategoryId)
+		}
+		if filter.StorageLocationId != 0 {
+			db = db.Where("products.storage_location_id = ?", filter.StorageLocationId)
+		}
+		if filter.Type != "" {
+			db = db.Where("products.type = ?", filter.Type)
+		}
+		if filter.Name != "" {
+			db = db.Where("products.name ILIKE ?", "%"+escapeLike(filter.Name)+"%")
+		}
+		if len(filter.ExpiryRanges) > 0 {
+			conditions := db.Session(&gorm.Session{NewDB: true})
+			for i, r := range filter.ExpiryRanges {
+				cond := db.Session(&gorm.Session{NewDB: true}).Where("products.type = ?", r.Type)
+				if r.From != nil {
+					cond = cond.Where("products.effective_expiry_date >= ?", *r.From)
+				}
+				if r.Before != nil {
+					cond = cond.Where("products.effective_expiry_date < ?", *r.Before)
+				}
+				if i == 0 {
+					conditions = conditions.Where(cond)
+				} else {
+					conditions = conditions.Or(cond)
+				}
+			}
+			db = db.Where(conditions)
+		}
+		return db
+	}
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (pr *productRepository) GetProductById(product *model.Product, householdId uint, productId uint) error {
+	if err := pr.db.Joins("User").Joins("Category").Joins("StorageLocation").Where("products.household_id = ?", householdId).First(product, productId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("product not found")
+		}
 		return err
 	}
 	return nil
 }
 
 func (pr *productRepository) CreateProduct(product *model.Product) error {
-	if err := pr.db.Create(product).Error; err != nil {
+	if err := pr.db.Omit(clause.Associations).Create(product).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
-func (pr *productRepository) UpdateProduct(product *model.Product, userId uint, productId uint) error {
-	result := pr.db.Model(&model.Product{}).Clauses(clause.Returning{}).Where("user_id = ? AND id = ?", userId, productId).Updates(product)
+func (pr *productRepository) UpdateProduct(product *model.Product, householdId uint, productId uint) error {
+	result := pr.db.Model(&model.Product{}).Clauses(clause.Returning{}).Where("household_id = ? AND id = ?", householdId, productId).
+		Select("name", "description", "quantity", "expiry_date", "type", "category_id", "storage_location_id",
+			"days_after_opening", "effective_expiry_date", "is_notified").Updates(product)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("product not found")
+	}
+	return nil
+}
+
+// UpdateProductFields writes only the given columns of product, including zero
+// values, so state transitions such as opening can be saved without a full update.
+func (pr *productRepository) UpdateProductFields(product *model.Product, householdId uint, productId uint, columns ...string) error {
+	result := pr.db.Model(&model.Product{}).Where("household_id = ? AND id = ?", householdId, productId).Select(columns).Updates(product)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("object does not exist")
+		return apperror.NotFound("product not found")
 	}
 	return nil
 }
 
-func (pr *productRepository) DeleteProduct(userId uint, productId uint) error {
-	result := pr.db.Where("user_id = ? AND id = ?", userId, productId).Delete(&model.Product{})
+func (pr *productRepository) DeleteProduct(householdId uint, productId uint) error {
+	result := pr.db.Where("household_id = ? AND id = ?", householdId, productId).Delete(&model.Product{})
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("product not found")
+		return apperror.NotFound("product not found")
+	}
+	return nil
+}
+
+// DecrementQuantity subtracts event.Amount from the product and records event in
+// the same transaction. The product is archived when its quantity reaches zero.
+// The update is conditional on the remaining quantity, so concurrent requests
+// can never drive it below zero.
+func (pr *productRepository) DecrementQuantity(product *model.Product, event *model.ConsumptionEvent, householdId uint, productId uint) error {
+	return pr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(product).Clauses(clause.Returning{}).
+			Where("household_id = ? AND id = ? AND archived_at IS NULL AND quantity >= ?", householdId, productId, event.Amount).
+			Updates(map[string]interface{}{
+				"quantity":    gorm.Expr("quantity - ?", event.Amount),
+				"archived_at": gorm.Expr("CASE WHEN quantity = ? THEN ?::timestamptz ELSE NULL END", event.Amount, event.OccurredAt),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.Conflict("product quantity has changed")
+		}
+
+		event.ProductId = productId
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func (pr *productRepository) GetConsumptionEvents(events *[]model.ConsumptionEvent, householdId uint, productId uint) error {
+	if err := pr.db.Joins("JOIN products ON products.id = consumption_events.product_id").
+		Where("products.household_id = ? AND consumption_events.product_id = ?", householdId, productId).
+		Order("consumption_events.occurred_at, consumption_events.id").Find(events).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *productRepository) GetNotificationCandidates(products *[]model.Product, until time.Time) error {
+	if err := pr.db.Where("products.is_notified = ? AND products.archived_at IS NULL AND products.effective_expiry_date <= ?", false, until).Order("products.effective_expiry_date").Find(products).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarkNotified takes a product whose notifications have all been delivered out
+// of the candidates. It leaves the product alone if its effective expiry date
+// changed since expiryDate, because that change re-arms the notification.
+func (pr *productRepository) MarkNotified(productId uint, expiryDate time.Time) error {
+	if err := pr.db.Model(&model.Product{}).Where("id = ? AND effective_expiry_date = ?", productId, expiryDate).
+		Update("is_notified", true).Error; err != nil {
+		return err
 	}
 	return nil
 }
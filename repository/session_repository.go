@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// activeSession matches sessions that have been neither revoked nor expired.
+const activeSession = "revoked_at IS NULL AND expires_at > ?"
+
+type ISessionRepository interface {
+	CreateSession(session *model.Session) error
+	GetActiveSession(session *model.Session, sessionId uint, now time.Time) error
+	GetActiveSessionsByUserId(sessions *[]model.Session, userId uint, now time.Time) error
+	RotateSession(session *model.Session, refreshTokenHash string, now time.Time) error
+	RevokeSession(userId uint, sessionId uint, now time.Time) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) ISessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (sr *sessionRepository) CreateSession(session *model.Session) error {
+	if err := sr.db.Omit(clause.Associations).Create(session).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *sessionRepository) GetActiveSession(session *model.Session, sessionId uint, now time.Time) error {
+	if err := sr.db.Where("id = ?", sessionId).Where(activeSession, now).First(session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("session not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (sr *sessionRepository) GetActiveSessionsByUserId(sessions *[]model.Session, userId uint, now time.Time) error {
+	if err := sr.db.Where("user_id = ?", userId).Where(activeSession, now).
+		Order("last_used_at DESC, id DESC").Find(sessions).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// RotateSession replaces the refresh token hash of session and extends it.
+// The update only applies while the stored hash is still the one session was
+// read with, so of two concurrent refreshes with the same token only one wins.
+func (sr *sessionRepository) RotateSession(session *model.Session, refreshTokenHash string, now time.Time) error {
+	result := sr.db.Model(&model.Session{}).
+		Where("id = ? AND refresh_token_hash = ?", session.ID, session.RefreshTokenHash).Where(activeSession, now).
+		Updates(map[string]interface{}{
+			"refresh_token_hash": refreshTokenHash,
+			"user_agent":         session.UserAgent,
+			"ip_address":         session.IPAddress,
+			"last_used_at":       now,
+			"expires_at":         now.Add(model.RefreshTokenLifetime),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.Unauthorized("session is no longer valid")
+	}
+	session.RefreshTokenHash = refreshTokenHash
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(model.RefreshTokenLifetime)
+	return nil
+}
+
+func (sr *sessionRepository) RevokeSession(userId uint, sessionId uint, now time.Time) error {
+	result := sr.db.Model(&model.Session{}).Where("id = ? AND user_id = ?", sessionId, userId).
+		Where(activeSession, now).Update("revoked_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("session not found")
+	}
+	return nil
+}
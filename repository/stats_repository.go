@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"expiry_tracker/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IStatsRepository interface {
+	GetExpiredUnused(buckets *[]model.ExpiredStatsBucket, householdId uint, period string, from time.Time, before time.Time) error
+	GetWasteByType(stats *[]model.WasteByTypeStats, householdId uint, before time.Time) error
+	GetWasteByCategory(stats *[]model.WasteByCategoryStats, householdId uint, before time.Time) error
+	GetConsumptionTime(stats *model.ConsumptionTimeStats, householdId uint) error
+	CountExpiring(count *int64, householdId uint, from *time.Time, before time.Time) error
+}
+
+type statsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) IStatsRepository {
+	return &statsRepository{db: db}
+}
+
+// GetExpiredUnused buckets products that expired in [from, before) while still
+// having remaining quantity, by the week or month of their expiry date.
+// Expiry dates are calendar dates stored as midnight UTC.
+func (sr *statsRepository) GetExpiredUnused(buckets *[]model.ExpiredStatsBucket, householdId uint, period string, from time.Time, before time.Time) error {
+	if err := sr.db.Raw(`
+		SELECT to_char(date_trunc(?, products.effective_expiry_date AT TIME ZONE 'UTC'), 'YYYY-MM-DD') AS period_start,
+			COUNT(*) AS products,
+			COALESCE(SUM(products.quantity), 0) AS quantity
+		FROM products
+		WHERE products.household_id = ? AND products.deleted_at IS NULL AND products.archived_at IS NULL
+			AND products.quantity > 0 AND products.effective_expiry_date >= ? AND products.effective_expiry_date < ?
+		GROUP BY 1
+		ORDER BY 1`,
+		period, householdId, from, before,
+	).Scan(buckets).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetWasteByType sums consumed and wasted amounts per expiry type. Remaining
+// quantity of products that expired before `before` counts as wasted until it
+// is explicitly discarded.
+func (sr *statsRepository) GetWasteByType(stats *[]model.WasteByTypeStats, householdId uint, before time.Time) error {
+	if err := sr.db.Raw(`
+		SELECT t.type, SUM(t.consumed) AS consumed, SUM(t.wasted) AS wasted
+		FROM (
+			SELECT products.type,
+				CASE WHEN consumption_events.kind = ? THEN consumption_events.amount ELSE 0 END AS consumed,
+				CASE WHEN consumption_events.kind = ? THEN consumption_events.amount ELSE 0 END AS wasted
+			FROM consumption_events
+			JOIN products ON products.id = consumption_events.product_id
+			WHERE products.household_id = ? AND products.deleted_at IS NULL
+			UNION ALL
+			SELECT products.type, 0, products.quantity
+			FROM products
+			WHERE products.household_id = ? AND products.deleted_at IS NULL AND products.archived_at IS NULL
+				AND products.effective_expiry_date < ?
+		) t
+		GROUP BY t.type
+		ORDER BY t.type`,
+		model.ConsumptionEventConsumed, model.ConsumptionEventWasted, householdId, householdId, before,
+	).Scan(stats).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetWasteByCategory is GetWasteByType grouped by product category instead.
+func (sr *statsRepository) GetWasteByCategory(stats *[]model.WasteByCategoryStats, householdId uint, before time.Time) error {
+	if err := sr.db.Raw(`
+		SELECT t.category_id, COALESCE(categories.name, '') AS category_name,
+			SUM(t.consumed) AS consumed, SUM(t.wasted) AS wasted
+		FROM (
+			SELECT products.category_id,
+				CASE WHEN consumption_events.kind = ? THEN consumption_events.amount ELSE 0 END AS consumed,
+				CASE WHEN consumption_events.kind = ? THEN consumption_events.amount ELSE 0 END AS wasted
+			FROM consumption_events
+			JOIN products ON products.id = consumption_events.product_id
+			WHERE products.household_id = ? AND products.deleted_at IS NULL
+			UNION ALL
+			SELECT products.category_id, 0, products.quantity
+			FROM products
+			WHERE products.household_id = ? AND products.deleted_at IS NULL AND products.archived_at IS NULL
+				AND products.effective_expiry_date < ?
+		) t
+		LEFT JOIN categories ON categories.id = t.category_id AND categories.deleted_at IS NULL
+		GROUP BY t.category_id, categories.name
+		ORDER BY categories.name NULLS LAST`,
+		model.ConsumptionEventConsumed, model.ConsumptionEventWasted, householdId, householdId, before,
+	).Scan(stats).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetConsumptionTime averages the days between a product being registered and
+// being consumed, weighted by the consumed amount.
+func (sr *statsRepository) GetConsumptionTime(stats *model.ConsumptionTimeStats, householdId uint) error {
+	if err := sr.db.Raw(`
+		SELECT COALESCE(SUM(EXTRACT(EPOCH FROM (consumption_events.occurred_at - products.created_at)) / 86400 * consumption_events.amount)
+				/ NULLIF(SUM(consumption_events.amount), 0), 0) AS average_days,
+			COUNT(*) AS consumptions
+		FROM consumption_events
+		JOIN products ON products.id = consumption_events.product_id
+		WHERE products.household_id = ? AND products.deleted_at IS NULL AND consumption_events.kind = ?`,
+		householdId, model.ConsumptionEventConsumed,
+	).Scan(stats).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *statsRepository) CountExpiring(count *int64, householdId uint, from *time.Time, before time.Time) error {
+	query := sr.db.Model(&model.Product{}).
+		Where("products.household_id = ? AND products.archived_at IS NULL AND products.effective_expiry_date < ?", householdId, before)
+	if from != nil {
+		query = query.Where("products.effective_expiry_date >= ?", *from)
+	}
+	if err := query.Count(count).Error; err != nil {
+		return err
+	}
+	return nil
+}
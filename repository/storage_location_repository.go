@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IStorageLocationRepository interface {
+	GetAllStorageLocations(locations *[]model.StorageLocation, householdId uint) error
+	GetStorageLocationById(location *model.StorageLocation, householdId uint, locationId uint) error
+	CreateStorageLocation(location *model.StorageLocation) error
+	UpdateStorageLocation(location *model.StorageLocation, householdId uint, locationId uint) error
+	DeleteStorageLocation(householdId uint, locationId uint) error
+}
+
+type storageLocationRepository struct {
+	db *gorm.DB
+}
+
+func NewStorageLocationRepository(db *gorm.DB) IStorageLocationRepository {
+	return &storageLocationRepository{db: db}
+}
+
+func (lr *storageLocationRepository) GetAllStorageLocations(locations *[]model.StorageLocation, householdId uint) error {
+	if err := lr.db.Where("household_id = ?", householdId).Order("name, id").Find(locations).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (lr *storageLocationRepository) GetStorageLocationById(location *model.StorageLocation, householdId uint, locationId uint) error {
+	if err := lr.db.Where("household_id = ?", householdId).First(location, locationId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("storage location not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (lr *storageLocationRepository) CreateStorageLocation(location *model.StorageLocation) error {
+	if err := lr.db.Create(location).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (lr *storageLocationRepository) UpdateStorageLocation(location *model.StorageLocation, householdId uint, locationId uint) error {
+	result := lr.db.Model(location).Clauses(clause.Returning{}).Where("household_id = ? AND id = ?", householdId, locationId).
+		Updates(map[string]interface{}{"name": location.Name, "kind": location.Kind})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("storage location not found")
+	}
+	return nil
+}
+
+// DeleteStorageLocation soft-deletes the location and detaches it from all products.
+func (lr *storageLocationRepository) DeleteStorageLocation(householdId uint, locationId uint) error {
+	return lr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("household_id = ? AND id = ?", householdId, locationId).Delete(&model.StorageLocation{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFound("storage location not found")
+		}
+		if err := tx.Model(&model.Product{}).Where("storage_location_id = ?", locationId).Update("storage_location_id", nil).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
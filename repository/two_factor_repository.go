@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ITwoFactorRepository interface {
+	SetTotpSecret(userId uint, sealedSecret string) error
+	EnableTotp(userId uint, step int64, codeHashes []string, now time.Time) error
+	DisableTotp(userId uint) error
+	UseTotpStep(userId uint, step int64) (bool, error)
+	GetUnusedRecoveryCodes(codes *[]model.RecoveryCode, userId uint) error
+	UseRecoveryCode(codeId uint, now time.Time) (bool, error)
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) ITwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+// SetTotpSecret stores a secret pending confirmation. It fails once two-factor
+// authentication is enabled, so enrolling again cannot replace a working
+// secret.
+func (tr *twoFactorRepository) SetTotpSecret(userId uint, sealedSecret string) error {
+	result := tr.db.Model(&model.User{}).Where("id = ? AND totp_enabled_at IS NULL", userId).
+		Updates(map[string]interface{}{"totp_secret": sealedSecret, "totp_last_step": 0})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.Conflict("two-factor authentication is already enabled")
+	}
+	return nil
+}
+
+// EnableTotp enables two-factor authentication with the pending secret and
+// replaces the user's recovery codes in one transaction.
+func (tr *twoFactorRepository) EnableTotp(userId uint, step int64, codeHashes []string, now time.Time) error {
+	return tr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).Where("id = ? AND totp_enabled_at IS NULL AND totp_secret <> ''", userId).
+			Updates(map[string]interface{}{"totp_enabled_at": now, "totp_last_step": step})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.Conflict("two-factor authentication is already enabled")
+		}
+
+		if err := tx.Where("user_id = ?", userId).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := []model.RecoveryCode{}
+		for _, h := range codeHashes {
+			codes = append(codes, model.RecoveryCode{UserId: userId, CodeHash: h})
+		}
+		if err := tx.Create(&codes).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func (tr *twoFactorRepository) DisableTotp(userId uint) error {
+	return tr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.User{}).Where("id = ?", userId).
+			Updates(map[string]interface{}{"totp_secret": "", "totp_enabled_at": nil, "totp_last_step": 0}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// UseTotpStep records step as the last accepted time step. It reports false
+// if a code from the same or a later step was already accepted.
+func (tr *twoFactorRepository) UseTotpStep(userId uint, step int64) (bool, error) {
+	result := tr.db.Model(&model.User{}).Where("id = ? AND totp_last_step < ?", userId, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (tr *twoFactorRepository) GetUnusedRecoveryCodes(codes *[]model.RecoveryCode, userId uint) error {
+	if err := tr.db.Where("user_id = ? AND used_at IS NULL", userId).Order("id").Find(codes).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// UseRecoveryCode marks the code as used and reports false if it already was.
+func (tr *twoFactorRepository) UseRecoveryCode(codeId uint, now time.Time) (bool, error) {
+	result := tr.db.Model(&model.RecoveryCode{}).Where("id = ? AND used_at IS NULL", codeId).Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IUserIdentityRepository interface {
+	GetIdentity(identity *model.UserIdentity, issuer string, subject string) error
+	CreateIdentity(identity *model.UserIdentity) error
+}
+
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) IUserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+func (ir *userIdentityRepository) GetIdentity(identity *model.UserIdentity, issuer string, subject string) error {
+	if err := ir.db.Where("issuer = ? AND subject = ?", issuer, subject).First(identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("identity not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (ir *userIdentityRepository) CreateIdentity(identity *model.UserIdentity) error {
+	if err := ir.db.Omit(clause.Associations).Create(identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return apperror.Conflict("identity already linked")
+		}
+		return err
+	}
+	return nil
+}
@@ -1,14 +1,23 @@
 package repository
 
 import (
+	"errors"
+	"expiry_tracker/apperror"
 	"expiry_tracker/model"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IUserRepository interface {
 	GetUserByEmail(user *model.User, email string) error
-	CreateUser(user *model.User) error
+	GetUserById(user *model.User, userId uint) error
+	CreateUser(user *model.User, household *model.Household) error
+	UpdateProfile(userId uint, name string, timeZone string) error
+	UpdateEmail(userId uint, email string) error
+	UpdatePassword(userId uint, passwordHash string, keepSessionId uint, now time.Time) error
+	DeleteUser(userId uint, now time.Time) error
 }
 
 type userRepository struct {
@@ -21,14 +30,155 @@ func NewUserRepository(db *gorm.DB) IUserRepository {
 
 func (ur *userRepository) GetUserByEmail(user *model.User, email string) error {
 	if err := ur.db.Where("email=?", email).First(user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("user not found")
+		}
 		return err
 	}
 	return nil
 }
 
-func (ur *userRepository) CreateUser(user *model.User) error {
-	if err := ur.db.Create(user).Error; err != nil {
+func (ur *userRepository) GetUserById(user *model.User, userId uint) error {
+	if err := ur.db.First(user, userId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound("user not found")
+		}
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// CreateUser creates the user together with household, owned by the user, so
+// that no user is ever left without a household.
+func (ur *userRepository) CreateUser(user *model.User, household *model.Household) error {
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return apperror.Conflict("email already registered")
+			}
+			return err
+		}
+		if err := tx.Create(household).Error; err != nil {
+			return err
+		}
+		member := model.HouseholdMember{HouseholdId: household.ID, UserId: user.ID, Role: model.HouseholdRoleOwner}
+		if err := tx.Omit(clause.Associations).Create(&member).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func (ur *userRepository) UpdateProfile(userId uint, name string, timeZone string) error {
+	result := ur.db.Model(&model.User{}).Where("id = ?", userId).
+		Updates(map[string]interface{}{"name": name, "time_zone": timeZone})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("user not found")
+	}
+	return nil
+}
+
+// UpdateEmail changes the user's email address and marks it as unverified.
+func (ur *userRepository) UpdateEmail(userId uint, email string) error {
+	result := ur.db.Model(&model.User{}).Where("id = ?", userId).
+		Updates(map[string]interface{}{"email": email, "email_verified_at": nil})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return apperror.Conflict("email already registered")
+		}
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("user not found")
+	}
+	return nil
+}
+
+// UpdatePassword sets a new password hash and revokes every session of the
+// user except keepSessionId, the one that changed the password.
+func (ur *userRepository) UpdatePassword(userId uint, passwordHash string, keepSessionId uint, now time.Time) error {
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).Where("id = ?", userId).Update("password", passwordHash)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFound("user not found")
+		}
+		if err := tx.Model(&model.Session{}).Where("user_id = ? AND id <> ?", userId, keepSessionId).
+			Where(activeSession, now).Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// DeleteUser soft deletes the user in one transaction. Households the user is
+// the only member of are deleted together with their products, categories
+// and storage locations; the user leaves shared households, which fails if
+// they are the last owner of one. All sessions of the user are revoked.
+func (ur *userRepository) DeleteUser(userId uint, now time.Time) error {
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		members := []model.HouseholdMember{}
+		if err := tx.Where("user_id = ?", userId).Find(&members).Error; err != nil {
+			return err
+		}
+		for _, m := range members {
+			// メンバーの増減と競合しないよう世帯をロックする
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model.Household{}, m.HouseholdId).Error; err != nil {
+				return err
+			}
+			others := []model.HouseholdMember{}
+			if err := tx.Where("household_id = ? AND user_id <> ?", m.HouseholdId, userId).Find(&others).Error; err != nil {
+				return err
+			}
+
+			if len(others) == 0 {
+				if err := tx.Delete(&model.Household{}, m.HouseholdId).Error; err != nil {
+					return err
+				}
+				if err := deleteHouseholdData(tx, m.HouseholdId); err != nil {
+					return err
+				}
+				continue
+			}
+			if m.Role == model.HouseholdRoleOwner && !hasOwner(others) {
+				return apperror.Conflict("transfer ownership of shared households before deleting the account")
+			}
+			if err := tx.Delete(&m).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&model.Session{}).Where("user_id = ?", userId).Where(activeSession, now).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&model.ApiToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&model.UserIdentity{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.User{}, userId)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFound("user not found")
+		}
+		return nil
+	})
+}
+
+func hasOwner(members []model.HouseholdMember) bool {
+	for _, m := range members {
+		if m.Role == model.HouseholdRoleOwner {
+			return true
+		}
+	}
+	return false
+}
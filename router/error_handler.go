@@ -0,0 +1,56 @@
+package router
+
+import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+var statusByKind = map[apperror.Kind]int{
+	apperror.KindValidation:      http.StatusBadRequest,
+	apperror.KindUnauthorized:    http.StatusUnauthorized,
+	apperror.KindForbidden:       http.StatusForbidden,
+	apperror.KindNotFound:        http.StatusNotFound,
+	apperror.KindConflict:        http.StatusConflict,
+	apperror.KindTooManyRequests: http.StatusTooManyRequests,
+}
+
+func httpErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status := http.StatusInternalServerError
+	res := model.ErrorResponse{Message: http.StatusText(http.StatusInternalServerError)}
+
+	var appErr *apperror.Error
+	var httpErr *echo.HTTPError
+	switch {
+	case errors.As(err, &appErr):
+		if s, ok := statusByKind[appErr.Kind]; ok {
+			status = s
+			res.Message = appErr.Message
+			res.Errors = appErr.Fields
+		}
+	case errors.As(err, &httpErr):
+		status = httpErr.Code
+		res.Message = fmt.Sprint(httpErr.Message)
+	}
+
+	if status == http.StatusInternalServerError {
+		c.Logger().Error(err)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, res)
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
@@ -0,0 +1,92 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "バリデーションエラー",
+			err:         apperror.Invalid("invalid product id"),
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "invalid product id",
+		},
+		{
+			name:        "認証エラー",
+			err:         apperror.Unauthorized("invalid password"),
+			wantStatus:  http.StatusUnauthorized,
+			wantMessage: "invalid password",
+		},
+		{
+			name:        "権限不足",
+			err:         apperror.Forbidden("insufficient household role"),
+			wantStatus:  http.StatusForbidden,
+			wantMessage: "insufficient household role",
+		},
+		{
+			name:        "存在しない",
+			err:         apperror.NotFound("product not found"),
+			wantStatus:  http.StatusNotFound,
+			wantMessage: "product not found",
+		},
+		{
+			name:        "重複",
+			err:         apperror.Conflict("email already registered"),
+			wantStatus:  http.StatusConflict,
+			wantMessage: "email already registered",
+		},
+		{
+			name:        "リクエストが多すぎる",
+			err:         apperror.TooManyRequests("verification email was sent recently"),
+			wantStatus:  http.StatusTooManyRequests,
+			wantMessage: "verification email was sent recently",
+		},
+		{
+			name:        "EchoのHTTPError",
+			err:         echo.NewHTTPError(http.StatusForbidden, "invalid csrf token"),
+			wantStatus:  http.StatusForbidden,
+			wantMessage: "invalid csrf token",
+		},
+		{
+			name:        "想定外のエラーは詳細を返さない",
+			err:         errors.New("pq: connection refused"),
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: "Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+			httpErrorHandler(tt.err, c)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", rec.Code, tt.wantStatus)
+			}
+			res := model.ErrorResponse{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatalf("invalid JSON body: %v", err)
+			}
+			if res.Message != tt.wantMessage {
+				t.Errorf("message = %v, want %v", res.Message, tt.wantMessage)
+			}
+		})
+	}
+}
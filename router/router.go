@@ -1,45 +1,172 @@
 package router
 
 import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/config"
 	"expiry_tracker/controller"
+	"expiry_tracker/metrics"
 	"net/http"
-	"os"
 
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
-func NewRouter(uc controller.IUserController, pc controller.IProductController) *echo.Echo {
+func NewRouter(
+	cfg config.Config,
+	uc controller.IUserController,
+	pc controller.IProductController,
+	sc controller.IStatsController,
+	cc controller.ICategoryController,
+	lc controller.IStorageLocationController,
+	hc controller.IHouseholdController,
+	ic controller.IHouseholdInvitationController,
+	ssc controller.ISessionController,
+	prc controller.IPasswordResetController,
+	evc controller.IEmailVerificationController,
+	tfc controller.ITwoFactorController,
+	atc controller.IApiTokenController,
+	oc controller.IOidcController,
+	hlc controller.IHealthController,
+	authRateLimit echo.MiddlewareFunc,
+	mt *metrics.Metrics,
+) *echo.Echo {
 	e := echo.New()
+	e.HTTPErrorHandler = httpErrorHandler
+	// CORS や CSRF で拒否されたリクエストも記録するため最初に登録する
+	e.Use(mt.Middleware())
+	// X-Forwarded-For は偽装できるため、リバースプロキシの背後にある場合のみ信頼する
+	if cfg.Server.TrustProxyHeaders {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"http://localhost:3000", os.Getenv("FE_URL")},
+		AllowOrigins: []string{"http://localhost:3000", cfg.Server.FEURL},
 		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept,
-			echo.HeaderAccessControlAllowHeaders, echo.HeaderXCSRFToken},
+			echo.HeaderAccessControlAllowHeaders, echo.HeaderXCSRFToken, controller.HeaderHouseholdId},
 		AllowMethods:     []string{"GET", "PUT", "POST", "DELETE"},
 		AllowCredentials: true,
 	}))
 	e.Use(middleware.CSRFWithConfig(middleware.CSRFConfig{
+		// Cookie を使わない API トークンのリクエストとヘルスチェックは CSRF の対象外
+		Skipper: func(c echo.Context) bool {
+			return controller.IsApiTokenRequest(c) || controller.IsHealthCheck(c) || c.Path() == "/metrics"
+		},
 		CookiePath:     "/",
-		CookieDomain:   os.Getenv("API_DOMAIN"),
+		CookieDomain:   cfg.Server.APIDomain,
 		CookieHTTPOnly: true,
 		CookieSameSite: http.SameSiteNoneMode,
 		// CookieSameSite: http.SameSiteDefaultMode,
 		//CookieMaxAge:   60,
 	}))
-	e.POST("/signup", uc.SignUp)
-	e.POST("/login", uc.Login)
+	// Docker やリバースプロキシ、Prometheus から呼び出す（/metrics は METRICS_TOKEN を設定した場合のみ認証する）
+	e.GET("/healthz", hlc.Healthz)
+	e.GET("/readyz", hlc.Readyz)
+	e.GET("/version", hlc.Version)
+	e.GET("/metrics", mt.Handler(cfg.Server.MetricsToken))
+	e.POST("/signup", uc.SignUp, authRateLimit)
+	e.POST("/login", uc.Login, authRateLimit)
+	e.POST("/login/2fa", tfc.Login, authRateLimit)
+	e.POST("/refresh", uc.RefreshToken)
 	e.POST("/logout", uc.LogOut)
+	e.POST("/password/forgot", prc.ForgotPassword, authRateLimit)
+	e.POST("/password/reset", prc.ResetPassword, authRateLimit)
+	e.POST("/email/verify", evc.VerifyEmail, authRateLimit)
 	e.GET("/csrf", uc.CsrfToken)
-	p := e.Group("/products")
-	p.Use(echojwt.WithConfig(echojwt.Config{
-		SigningKey:  []byte(os.Getenv("SECRET")),
+	// OIDC_ISSUER を設定した場合のみ ID プロバイダーでのログインを有効にする
+	if oc != nil {
+		e.GET("/oidc/login", oc.Login, authRateLimit)
+		e.GET("/oidc/callback", oc.Callback, authRateLimit)
+	}
+	jwtMiddleware := echojwt.WithConfig(echojwt.Config{
+		SigningKey:  []byte(cfg.Secret),
 		TokenLookup: "cookie:token",
-	}))
+		ErrorHandler: func(c echo.Context, err error) error {
+			var extractErr *echojwt.TokenExtractionError
+			if !errors.As(err, &extractErr) {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired jwt").SetInternal(err)
+			}
+			// Cookie のセッションが必要な操作に API トークンで来た場合は、トークンの権限不足として拒否する
+			if controller.IsApiTokenRequest(c) {
+				return apperror.Forbidden("api tokens cannot access this endpoint")
+			}
+			return echo.NewHTTPError(http.StatusBadRequest, "missing or malformed jwt").SetInternal(err)
+		},
+	})
+	// 在庫の操作は API トークンでも呼び出せる。アカウント・世帯・トークンの管理は Cookie のセッションのみ
+	apiAuth := atc.BearerAuth(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return jwtMiddleware(ssc.VerifySession(next))
+	})
+	p := e.Group("/products")
+	p.Use(apiAuth)
 	p.GET("", pc.GetAllProducts)
 	p.GET("/:productId", pc.GetProductById)
 	p.POST("", pc.CreateProduct)
 	p.PUT("/:productId", pc.UpdateProduct)
 	p.DELETE("/:productId", pc.DeleteProduct)
+	p.POST("/:productId/consume", pc.ConsumeProduct)
+	p.POST("/:productId/discard", pc.DiscardProduct)
+	p.GET("/:productId/events", pc.GetConsumptionEvents)
+	p.POST("/:productId/open", pc.OpenProduct)
+	p.POST("/:productId/freeze", pc.FreezeProduct)
+	p.POST("/:productId/thaw", pc.ThawProduct)
+	c := e.Group("/categories")
+	c.Use(apiAuth)
+	c.GET("", cc.GetAllCategories)
+	c.POST("", cc.CreateCategory)
+	c.PUT("/:categoryId", cc.UpdateCategory)
+	c.DELETE("/:categoryId", cc.DeleteCategory)
+	l := e.Group("/locations")
+	l.Use(apiAuth)
+	l.GET("", lc.GetAllStorageLocations)
+	l.POST("", lc.CreateStorageLocation)
+	l.PUT("/:locationId", lc.UpdateStorageLocation)
+	l.DELETE("/:locationId", lc.DeleteStorageLocation)
+	h := e.Group("/households")
+	h.Use(jwtMiddleware, ssc.VerifySession)
+	h.GET("", hc.GetAllHouseholds)
+	h.POST("", hc.CreateHousehold)
+	h.GET("/:householdId", hc.GetHouseholdById)
+	h.PUT("/:householdId", hc.UpdateHousehold)
+	h.DELETE("/:householdId", hc.DeleteHousehold)
+	h.PUT("/:householdId/members/:userId", hc.UpdateMemberRole)
+	h.DELETE("/:householdId/members/:userId", hc.RemoveMember)
+	h.POST("/:householdId/invitations", ic.CreateInvitation)
+	h.GET("/:householdId/invitations", ic.GetPendingInvitations)
+	h.DELETE("/:householdId/invitations/:invitationId", ic.RevokeInvitation)
+	i := e.Group("/invitations")
+	i.Use(jwtMiddleware, ssc.VerifySession)
+	i.POST("/accept", ic.AcceptInvitation)
+	i.POST("/decline", ic.DeclineInvitation)
+	ses := e.Group("/sessions")
+	ses.Use(jwtMiddleware, ssc.VerifySession)
+	ses.GET("", ssc.GetSessions)
+	ses.DELETE("/:sessionId", ssc.RevokeSession)
+	e.POST("/email/verify/resend", evc.ResendVerification, jwtMiddleware, ssc.VerifySession)
+	m := e.Group("/me")
+	m.Use(jwtMiddleware, ssc.VerifySession)
+	m.GET("", uc.GetMe)
+	m.PUT("", uc.UpdateProfile)
+	m.PUT("/email", uc.ChangeEmail)
+	m.PUT("/password", uc.ChangePassword)
+	m.DELETE("", uc.DeleteAccount)
+	t := e.Group("/tokens")
+	t.Use(jwtMiddleware, ssc.VerifySession)
+	t.GET("", atc.GetApiTokens)
+	t.POST("", atc.CreateApiToken)
+	t.DELETE("/:tokenId", atc.DeleteApiToken)
+	tf := e.Group("/2fa")
+	tf.Use(jwtMiddleware, ssc.VerifySession)
+	tf.POST("/enroll", tfc.Enroll)
+	tf.POST("/confirm", tfc.Confirm)
+	tf.POST("/disable", tfc.Disable)
+	s := e.Group("/stats")
+	s.Use(apiAuth)
+	s.GET("/expired", sc.GetExpiredStats)
+	s.GET("/waste", sc.GetWasteStats)
+	s.GET("/consumption-time", sc.GetConsumptionTimeStats)
+	s.GET("/upcoming", sc.GetUpcomingExpiryStats)
 	return e
 }
@@ -0,0 +1,89 @@
+package router
+
+import (
+	"expiry_tracker/config"
+	"expiry_tracker/controller"
+	"expiry_tracker/metrics"
+	"expiry_tracker/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// fakeApiTokenUsecase はどのトークンも書き込み権限のある有効なトークンとして扱う
+type fakeApiTokenUsecase struct {
+	authenticated int
+}
+
+func (f *fakeApiTokenUsecase) CreateApiToken(request model.ApiTokenRequest, userId uint) (model.ApiTokenResponse, error) {
+	return model.ApiTokenResponse{}, nil
+}
+
+func (f *fakeApiTokenUsecase) GetApiTokens(userId uint) ([]model.ApiTokenResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeApiTokenUsecase) DeleteApiToken(userId uint, tokenId uint) error {
+	return nil
+}
+
+func (f *fakeApiTokenUsecase) Authenticate(token string) (model.ApiToken, error) {
+	f.authenticated++
+	return model.ApiToken{ID: 1, UserId: 1, Scope: model.ApiTokenScopeWrite}, nil
+}
+
+func TestNewRouter_HouseholdsRejectApiTokens(t *testing.T) {
+	tokens := &fakeApiTokenUsecase{}
+	// 認証を通過してハンドラーが呼ばれると、ユースケースが nil のため panic する
+	e := NewRouter(
+		config.Config{Secret: "0123456789abcdef0123456789abcdef"},
+		controller.NewUserController(nil, ""),
+		controller.NewProductController(nil),
+		controller.NewStatsController(nil),
+		controller.NewCategoryController(nil),
+		controller.NewStorageLocationController(nil),
+		controller.NewHouseholdController(nil),
+		controller.NewHouseholdInvitationController(nil),
+		controller.NewSessionController(nil),
+		controller.NewPasswordResetController(nil, ""),
+		controller.NewEmailVerificationController(nil),
+		controller.NewTwoFactorController(nil, ""),
+		controller.NewApiTokenController(tokens),
+		nil,
+		controller.NewHealthController(nil),
+		func(next echo.HandlerFunc) echo.HandlerFunc { return next },
+		metrics.New(),
+	)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+	}{
+		{name: "世帯の削除", method: http.MethodDelete, path: "/households/1"},
+		{name: "世帯の名前の変更", method: http.MethodPut, path: "/households/1"},
+		{name: "メンバーの役割の変更", method: http.MethodPut, path: "/households/1/members/2"},
+		{name: "メンバーを外す", method: http.MethodDelete, path: "/households/1/members/2"},
+		{name: "招待の発行", method: http.MethodPost, path: "/households/1/invitations"},
+		{name: "招待の一覧", method: http.MethodGet, path: "/households/1/invitations"},
+		{name: "世帯の一覧", method: http.MethodGet, path: "/households"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer fk_test")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+			}
+		})
+	}
+	if tokens.authenticated != 0 {
+		t.Errorf("Authenticate() called %d times, want the token to be ignored", tokens.authenticated)
+	}
+}
@@ -0,0 +1,202 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockIssuer is a minimal OpenID Connect provider that issues an ID token for
+// a single authorization code.
+type mockIssuer struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	challenge string
+	nonce     string
+	claims    jwt.MapClaims
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                m.URL,
+			"authorization_endpoint":                m.URL + "/authorize",
+			"token_endpoint":                        m.URL + "/token",
+			"jwks_uri":                              m.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": "test",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != "code-123" || base64.RawURLEncoding.EncodeToString(sum[:]) != m.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		claims := jwt.MapClaims{
+			"iss":   m.URL,
+			"sub":   "user-1",
+			"aud":   "client-1",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": m.nonce,
+		}
+		for k, v := range m.claims {
+			claims[k] = v
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "test"
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   60,
+			"id_token":     idToken,
+		})
+	})
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+	return m
+}
+
+// authorize plays the part of the user agent: it follows the authorization
+// URL and records what the provider would have stored with the code.
+func (m *mockIssuer) authorize(t *testing.T, authURL string) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("code_challenge_method = %q, want S256", q.Get("code_challenge_method"))
+	}
+	m.challenge = q.Get("code_challenge")
+	m.nonce = q.Get("nonce")
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     jwt.MapClaims
+		verifier   string
+		nonce      string
+		wantErr    bool
+		wantEmail  string
+		wantVerify bool
+	}{
+		{
+			name:       "正常にログインできる",
+			claims:     jwt.MapClaims{"email": "user@example.com", "email_verified": true, "name": "User"},
+			wantEmail:  "user@example.com",
+			wantVerify: true,
+		},
+		{
+			name:      "メールアドレスが未確認",
+			claims:    jwt.MapClaims{"email": "user@example.com"},
+			wantEmail: "user@example.com",
+		},
+		{
+			name:     "code_verifier が一致しない",
+			verifier: "another-verifier-another-verifier-another-verifier",
+			wantErr:  true,
+		},
+		{
+			name:    "nonce が一致しない",
+			nonce:   "another-nonce",
+			wantErr: true,
+		},
+		{
+			name:    "別のクライアント向けの ID トークン",
+			claims:  jwt.MapClaims{"aud": "client-2"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newMockIssuer(t)
+			issuer.claims = tt.claims
+			p := NewProvider(Config{
+				Issuer:       issuer.URL,
+				ClientID:     "client-1",
+				ClientSecret: "secret",
+				RedirectURL:  "http://localhost:8080/oidc/callback",
+			})
+			ctx := context.Background()
+			verifier := "verifier-verifier-verifier-verifier-verifier-verifier"
+
+			authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+			if err != nil {
+				t.Fatalf("AuthCodeURL() error = %v", err)
+			}
+			issuer.authorize(t, authURL)
+
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			nonce := "nonce-1"
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+			identity, err := p.Exchange(ctx, "code-123", verifier, nonce)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidResponse) {
+					t.Errorf("Exchange() error = %v, want ErrInvalidResponse", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange() error = %v", err)
+			}
+			if identity.Issuer != issuer.URL || identity.Subject != "user-1" {
+				t.Errorf("Exchange() identity = %+v, want issuer %s and subject user-1", identity, issuer.URL)
+			}
+			if identity.Email != tt.wantEmail || identity.EmailVerified != tt.wantVerify {
+				t.Errorf("Exchange() email = %s (verified %v), want %s (verified %v)",
+					identity.Email, identity.EmailVerified, tt.wantEmail, tt.wantVerify)
+			}
+		})
+	}
+}
+
+func TestProvider_DiscoveryFailure(t *testing.T) {
+	p := NewProvider(Config{Issuer: "http://127.0.0.1:1", ClientID: "client-1"})
+	if _, err := p.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); err == nil {
+		t.Error("AuthCodeURL() error = nil, want discovery error")
+	}
+}
@@ -0,0 +1,98 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 Appendix B の SHA1 のテストベクタ
+func TestHOTP_RFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "94287082"},
+		{unix: 1111111109, want: "07081804"},
+		{unix: 1111111111, want: "14050471"},
+		{unix: 1234567890, want: "89005924"},
+		{unix: 2000000000, want: "69279037"},
+		{unix: 20000000000, want: "65353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := hotp(key, uint64(Step(time.Unix(tt.unix, 0))), 8); got != tt.want {
+				t.Errorf("hotp() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeAndValidate(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+
+	code, err := Code(secret, now)
+	if err != nil {
+		t.Fatalf("Code() error = %v", err)
+	}
+	if code != "050471" {
+		t.Errorf("Code() = %v, want 050471", code)
+	}
+
+	tests := []struct {
+		name   string
+		code   string
+		at     time.Time
+		wantOk bool
+	}{
+		{name: "同じ時間帯", code: code, at: now, wantOk: true},
+		{name: "1つ後の時間帯までは許容", code: code, at: now.Add(Period), wantOk: true},
+		{name: "1つ前の時間帯までは許容", code: code, at: now.Add(-Period), wantOk: true},
+		{name: "2つ後の時間帯は拒否", code: code, at: now.Add(2 * Period), wantOk: false},
+		{name: "誤ったコード", code: "000000", at: now, wantOk: false},
+		{name: "桁数が違う", code: "50471", at: now, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(secret, tt.code, tt.at)
+			if ok != tt.wantOk {
+				t.Fatalf("Validate() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && step != Step(now) {
+				t.Errorf("Validate() step = %v, want %v", step, Step(now))
+			}
+		})
+	}
+}
+
+func TestGenerateSecretAndURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("len(secret) = %d, want 32", len(secret))
+	}
+	if _, err := Code(secret, time.Now()); err != nil {
+		t.Errorf("Code() error = %v for a generated secret", err)
+	}
+
+	uri, err := url.Parse(URI("Fresh Keeper", "user@example.com", secret))
+	if err != nil {
+		t.Fatalf("URI() is not a valid URL: %v", err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" {
+		t.Errorf("URI() = %v, want otpauth://totp/...", uri)
+	}
+	if !strings.HasPrefix(uri.Path, "/Fresh Keeper:user@example.com") {
+		t.Errorf("URI() label = %q", uri.Path)
+	}
+	if uri.Query().Get("secret") != secret || uri.Query().Get("issuer") != "Fresh Keeper" {
+		t.Errorf("URI() query = %v", uri.Query())
+	}
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"expiry_tracker/validator"
+	"strings"
+	"time"
+)
+
+type IApiTokenUsecase interface {
+	CreateApiToken(request model.ApiTokenRequest, userId uint) (model.ApiTokenResponse, error)
+	GetApiTokens(userId uint) ([]model.ApiTokenResponse, error)
+	DeleteApiToken(userId uint, tokenId uint) error
+	Authenticate(token string) (model.ApiToken, error)
+}
+
+type apiTokenUsecase struct {
+	ar repository.IApiTokenRepository
+	av validator.IApiTokenValidator
+}
+
+func NewApiTokenUsecase(ar repository.IApiTokenRepository, av validator.IApiTokenValidator) IApiTokenUsecase {
+	return &apiTokenUsecase{ar: ar, av: av}
+}
+
+// CreateApiToken issues a personal access token. The token is returned only
+// in this response; the database keeps its SHA-256 hash, which is enough
+// because the token itself is random rather than chosen by the user.
+func (au *apiTokenUsecase) CreateApiToken(request model.ApiTokenRequest, userId uint) (model.ApiTokenResponse, error) {
+	if err := au.av.ApiTokenValidate(request); err != nil {
+		return model.ApiTokenResponse{}, apperror.Validation(err)
+	}
+	count, err := au.ar.CountApiTokens(userId)
+	if err != nil {
+		return model.ApiTokenResponse{}, err
+	}
+	if count >= model.MaxApiTokensPerUser {
+		return model.ApiTokenResponse{}, apperror.Conflict("too many api tokens")
+	}
+
+	secret, err := randomSecret()
+	if err != nil {
+		return model.ApiTokenResponse{}, err
+	}
+	tokenString := model.ApiTokenPrefix + secret
+	token := model.ApiToken{
+		UserId:    userId,
+		Name:      strings.TrimSpace(request.Name),
+		TokenHash: hashApiToken(tokenString),
+		Scope:     request.Scope,
+	}
+	if request.ExpiresInDays != 0 {
+		expiresAt := time.Now().AddDate(0, 0, request.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := au.ar.CreateApiToken(&token); err != nil {
+		return model.ApiTokenResponse{}, err
+	}
+
+	resToken := toApiTokenResponse(token)
+	resToken.Token = tokenString
+	return resToken, nil
+}
+
+func (au *apiTokenUsecase) GetApiTokens(userId uint) ([]model.ApiTokenResponse, error) {
+	tokens := []model.ApiToken{}
+	if err := au.ar.GetApiTokensByUserId(&tokens, userId); err != nil {
+		return nil, err
+	}
+
+	resTokens := []model.ApiTokenResponse{}
+	for _, v := range tokens {
+		resTokens = append(resTokens, toApiTokenResponse(v))
+	}
+	return resTokens, nil
+}
+
+func (au *apiTokenUsecase) DeleteApiToken(userId uint, tokenId uint) error {
+	if err := au.ar.DeleteApiToken(userId, tokenId); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Authenticate returns the active token matching tokenString and records
+// that it was used.
+func (au *apiTokenUsecase) Authenticate(tokenString string) (model.ApiToken, error) {
+	if !strings.HasPrefix(tokenString, model.ApiTokenPrefix) {
+		return model.ApiToken{}, apperror.Unauthorized("invalid api token")
+	}
+	now := time.Now()
+	token := model.ApiToken{}
+	if err := au.ar.GetActiveApiTokenByHash(&token, hashApiToken(tokenString), now); err != nil {
+		if apperror.KindOf(err) == apperror.KindNotFound {
+			return model.ApiToken{}, apperror.Unauthorized("invalid api token")
+		}
+		return model.ApiToken{}, err
+	}
+	if err := au.ar.TouchApiToken(token.ID, now); err != nil {
+		return model.ApiToken{}, err
+	}
+	return token, nil
+}
+
+func hashApiToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func toApiTokenResponse(token model.ApiToken) model.ApiTokenResponse {
+	return model.ApiTokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scope:      token.Scope,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"time"
+)
+
+// AttemptLimit configures an AttemptLimiter. The first FreeAttempts attempts
+// within Window are unrestricted; after that each attempt must wait BaseDelay,
+// doubling with every further attempt up to MaxDelay, which acts as a
+// temporary lockout.
+type AttemptLimit struct {
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Window       time.Duration
+}
+
+// Delay returns how long to wait after the count-th attempt.
+func (l AttemptLimit) Delay(count int) time.Duration {
+	if count < l.FreeAttempts {
+		return 0
+	}
+	delay := l.BaseDelay
+	for i := l.FreeAttempts; i < count && delay < l.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > l.MaxDelay {
+		delay = l.MaxDelay
+	}
+	return delay
+}
+
+type IAttemptLimiter interface {
+	Check(key string) error
+	Record(key string) error
+	Reset(key string) error
+}
+
+type attemptLimiter struct {
+	ar    repository.IAttemptCounterRepository
+	limit AttemptLimit
+	now   func() time.Time
+}
+
+func NewAttemptLimiter(ar repository.IAttemptCounterRepository, limit AttemptLimit) IAttemptLimiter {
+	return &attemptLimiter{ar: ar, limit: limit, now: time.Now}
+}
+
+// Check returns a too-many-requests error while key has to wait before its
+// next attempt.
+func (al *attemptLimiter) Check(key string) error {
+	counter := model.AttemptCounter{}
+	if err := al.ar.GetCounter(&counter, key); err != nil {
+		return err
+	}
+	if counter.Count == 0 {
+		return nil
+	}
+	if al.now().Before(counter.LastAttemptAt.Add(al.limit.Delay(counter.Count))) {
+		return apperror.TooManyRequests("too many attempts, please try again later")
+	}
+	return nil
+}
+
+func (al *attemptLimiter) Record(key string) error {
+	counter := model.AttemptCounter{}
+	if err := al.ar.IncrementCounter(&counter, key, al.now(), al.limit.Window); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (al *attemptLimiter) Reset(key string) error {
+	if err := al.ar.DeleteCounter(key); err != nil {
+		return err
+	}
+	return nil
+}
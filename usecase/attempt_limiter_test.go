@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/repository"
+	"testing"
+	"time"
+)
+
+func TestAttemptLimit_Delay(t *testing.T) {
+	limit := AttemptLimit{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second, Window: time.Hour}
+
+	tests := []struct {
+		name  string
+		count int
+		want  time.Duration
+	}{
+		{name: "試行なし", count: 0, want: 0},
+		{name: "無制限の範囲内", count: 2, want: 0},
+		{name: "制限の開始", count: 3, want: time.Second},
+		{name: "1回ごとに倍になる", count: 5, want: 4 * time.Second},
+		{name: "上限でロックアウト", count: 7, want: 10 * time.Second},
+		{name: "大きな回数でも上限を超えない", count: 1000, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := limit.Delay(tt.count); got != tt.want {
+				t.Errorf("Delay(%d) = %v, want %v", tt.count, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttemptLimiter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := &attemptLimiter{
+		ar:    repository.NewMemoryAttemptCounterRepository(),
+		limit: AttemptLimit{FreeAttempts: 2, BaseDelay: time.Minute, MaxDelay: 15 * time.Minute, Window: time.Hour},
+		now:   func() time.Time { return now },
+	}
+	record := func() {
+		t.Helper()
+		if err := limiter.Record("key"); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	wantLimited := func(want bool) {
+		t.Helper()
+		err := limiter.Check("key")
+		if limited := apperror.KindOf(err) == apperror.KindTooManyRequests; limited != want {
+			t.Errorf("Check() = %v, want limited %v", err, want)
+		}
+	}
+
+	record()
+	wantLimited(false)
+	record()
+	wantLimited(true)
+
+	now = now.Add(time.Minute)
+	wantLimited(false)
+	record()
+	// 3回目の失敗で待ち時間が2分に延びる
+	now = now.Add(time.Minute)
+	wantLimited(true)
+	now = now.Add(time.Minute)
+	wantLimited(false)
+
+	if err := limiter.Reset("key"); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	record()
+	wantLimited(false)
+
+	// 期間を過ぎた試行は数えない
+	record()
+	now = now.Add(2 * time.Hour)
+	record()
+	wantLimited(false)
+}
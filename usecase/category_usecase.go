@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"expiry_tracker/validator"
+)
+
+type ICategoryUsecase interface {
+	GetAllCategories(userId uint, householdId uint) ([]model.CategoryResponse, error)
+	CreateCategory(category model.Category, householdId uint) (model.CategoryResponse, error)
+	UpdateCategory(category model.Category, userId uint, householdId uint, categoryId uint) (model.CategoryResponse, error)
+	DeleteCategory(userId uint, householdId uint, categoryId uint) error
+}
+
+type categoryUsecase struct {
+	cr repository.ICategoryRepository
+	hr repository.IHouseholdRepository
+	cv validator.ICategoryValidator
+}
+
+func NewCategoryUsecase(cr repository.ICategoryRepository, hr repository.IHouseholdRepository, cv validator.ICategoryValidator) ICategoryUsecase {
+	return &categoryUsecase{cr: cr, hr: hr, cv: cv}
+}
+
+func (cu *categoryUsecase) GetAllCategories(userId uint, householdId uint) ([]model.CategoryResponse, error) {
+	member, err := authorizeHousehold(cu.hr, userId, householdId, model.HouseholdRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := []model.Category{}
+	if err := cu.cr.GetAllCategories(&categories, member.HouseholdId); err != nil {
+		return nil, err
+	}
+
+	resCategories := []model.CategoryResponse{}
+	for _, v := range categories {
+		resCategories = append(resCategories, toCategoryResponse(v))
+	}
+	return resCategories, nil
+}
+
+func (cu *categoryUsecase) CreateCategory(category model.Category, householdId uint) (model.CategoryResponse, error) {
+	if err := cu.cv.CategoryValidate(category); err != nil {
+		return model.CategoryResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(cu.hr, category.UserId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
+		return model.CategoryResponse{}, err
+	}
+	category.HouseholdId = member.HouseholdId
+	if err := cu.cr.CreateCategory(&category); err != nil {
+		return model.CategoryResponse{}, err
+	}
+	return toCategoryResponse(category), nil
+}
+
+func (cu *categoryUsecase) UpdateCategory(category model.Category, userId uint, householdId uint, categoryId uint) (model.CategoryResponse, error) {
+	if err := cu.cv.CategoryValidate(category); err != nil {
+		return model.CategoryResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(cu.hr, userId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
+		return model.CategoryResponse{}, err
+	}
+	if err := cu.cr.UpdateCategory(&category, member.HouseholdId, categoryId); err != nil {
+		return model.CategoryResponse{}, err
+	}
+	return toCategoryResponse(category), nil
+}
+
+func (cu *categoryUsecase) DeleteCategory(userId uint, householdId uint, categoryId uint) error {
+	member, err := authorizeHousehold(cu.hr, userId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := cu.cr.DeleteCategory(member.HouseholdId, categoryId); err != nil {
+		return err
+	}
+	return nil
+}
+
+func toCategoryResponse(category model.Category) model.CategoryResponse {
+	return model.CategoryResponse{
+		ID:                   category.ID,
+		Name:                 category.Name,
+		FreezerShelfLifeDays: category.FreezerShelfLifeDays,
+		CreatedAt:            category.CreatedAt,
+		UpdatedAt:            category.UpdatedAt,
+	}
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/mailer"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"expiry_tracker/validator"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type IEmailVerificationUsecase interface {
+	VerifyEmail(request model.VerifyEmailRequest) error
+	ResendVerification(userId uint) error
+}
+
+type emailVerificationUsecase struct {
+	er    repository.IEmailVerificationRepository
+	ur    repository.IUserRepository
+	m     mailer.IMailer
+	uv    validator.IUserValidator
+	feURL string
+	keys  TokenKeys
+}
+
+func NewEmailVerificationUsecase(
+	er repository.IEmailVerificationRepository,
+	ur repository.IUserRepository,
+	m mailer.IMailer,
+	uv validator.IUserValidator,
+	feURL string,
+	keys TokenKeys,
+) IEmailVerificationUsecase {
+	return &emailVerificationUsecase{er: er, ur: ur, m: m, uv: uv, feURL: feURL, keys: keys}
+}
+
+func (eu *emailVerificationUsecase) VerifyEmail(request model.VerifyEmailRequest) error {
+	if err := eu.uv.VerifyEmailValidate(request); err != nil {
+		return apperror.Validation(err)
+	}
+	claims, err := eu.keys.parseToken(tokenPurposeEmailVerification, request.Token)
+	if err != nil {
+		return err
+	}
+	verificationId, err := uintClaim(claims, "verification_id")
+	if err != nil {
+		return err
+	}
+	nonce, _ := claims["nonce"].(string)
+
+	verification := model.EmailVerification{}
+	if err := eu.er.GetEmailVerificationById(&verification, verificationId); err != nil {
+		if apperror.KindOf(err) == apperror.KindNotFound {
+			return apperror.Invalid("invalid or expired token")
+		}
+		return err
+	}
+	if !compareSecret(verification.TokenHash, nonce) {
+		return apperror.Invalid("invalid or expired token")
+	}
+	if err := eu.er.VerifyEmail(verification.ID, time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ResendVerification sends a new verification email, at most once per
+// EmailVerificationResendInterval and EmailVerificationDailyLimit times a day.
+func (eu *emailVerificationUsecase) ResendVerification(userId uint) error {
+	user := model.User{}
+	if err := eu.ur.GetUserById(&user, userId); err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return apperror.Conflict("email address is already verified")
+	}
+
+	now := time.Now()
+	var count int64
+	if err := eu.er.CountEmailVerificationsSince(&count, userId, now.Add(-model.EmailVerificationResendInterval)); err != nil {
+		return err
+	}
+	if count > 0 {
+		return apperror.TooManyRequests("verification email was sent recently")
+	}
+	if err := eu.er.CountEmailVerificationsSince(&count, userId, now.AddDate(0, 0, -1)); err != nil {
+		return err
+	}
+	if count >= model.EmailVerificationDailyLimit {
+		return apperror.TooManyRequests("too many verification emails today")
+	}
+
+	return sendEmailVerification(eu.keys, eu.feURL, eu.er, eu.m, user)
+}
+
+// sendEmailVerification mails user a link that verifies their current email
+// address.
+func sendEmailVerification(keys TokenKeys, feURL string, er repository.IEmailVerificationRepository, m mailer.IMailer, user model.User) error {
+	nonce, err := randomSecret()
+	if err != nil {
+		return err
+	}
+	hash, err := hashSecret(nonce)
+	if err != nil {
+		return err
+	}
+	verification := model.EmailVerification{
+		UserId:    user.ID,
+		Email:     user.Email,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(model.EmailVerificationLifetime),
+	}
+	if err := er.CreateEmailVerification(&verification); err != nil {
+		return err
+	}
+
+	token, err := keys.signToken(tokenPurposeEmailVerification, jwt.MapClaims{
+		"verification_id": verification.ID,
+		"nonce":           nonce,
+		"exp":             verification.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	link := feURL + "/verify-email?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("%s さん\n\n以下のリンクからメールアドレスを確認してください（%s まで有効）。"+
+		"確認が済むまで期限の通知は送られません。\n\n%s\n",
+		user.Name, verification.ExpiresAt.Format("2006-01-02 15:04"), link)
+	return m.Send(user.Email, "メールアドレスの確認", body)
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"time"
+)
+
+// ExpiryPolicy is the single definition of how urgent a product is. It is
+// shared by the product API and the notification worker.
+type ExpiryPolicy struct {
+	// 消費期限は安全性に関わるため、賞味期限より短い警告期間を想定
+	UseByWarningDays      int
+	BestBeforeWarningDays int
+	// カテゴリに冷凍保存の日数がない場合の既定値
+	FreezerShelfLifeDays int
+	// 解凍してから消費するまでの日数
+	ThawedShelfLifeDays int
+}
+
+func NewExpiryPolicy(useByWarningDays int, bestBeforeWarningDays int, freezerShelfLifeDays int, thawedShelfLifeDays int) ExpiryPolicy {
+	return ExpiryPolicy{
+		UseByWarningDays:      useByWarningDays,
+		BestBeforeWarningDays: bestBeforeWarningDays,
+		FreezerShelfLifeDays:  freezerShelfLifeDays,
+		ThawedShelfLifeDays:   thawedShelfLifeDays,
+	}
+}
+
+func (ep ExpiryPolicy) WarningDays(expiryType model.ExpiryType) int {
+	if expiryType == model.ExpiryTypeUseBy {
+		return ep.UseByWarningDays
+	}
+	return ep.BestBeforeWarningDays
+}
+
+// MaxWarningDays returns the longest warning window across all expiry types.
+func (ep ExpiryPolicy) MaxWarningDays() int {
+	return max(ep.UseByWarningDays, ep.BestBeforeWarningDays)
+}
+
+// DaysLeft returns the number of calendar days between today in loc and the
+// expiry date. Expiry dates are calendar dates stored as midnight UTC, so they
+// are read in UTC whatever loc is. It is 0 on the expiry day and negative
+// afterwards.
+func (ep ExpiryPolicy) DaysLeft(expiryDate time.Time, now time.Time, loc *time.Location) int {
+	return int(calendarDate(expiryDate, time.UTC).Sub(calendarDate(now, loc)).Hours() / 24)
+}
+
+func (ep ExpiryPolicy) Status(expiryType model.ExpiryType, daysLeft int) model.ExpiryStatus {
+	switch {
+	case daysLeft < 0:
+		return model.ExpiryStatusExpired
+	case daysLeft <= ep.WarningDays(expiryType):
+		return model.ExpiryStatusExpiringSoon
+	default:
+		return model.ExpiryStatusFresh
+	}
+}
+
+// FreezerShelfLife returns how many days a product in category keeps in the
+// freezer.
+func (ep ExpiryPolicy) FreezerShelfLife(category *model.Category) int {
+	if category != nil && category.FreezerShelfLifeDays != nil {
+		return *category.FreezerShelfLifeDays
+	}
+	return ep.FreezerShelfLifeDays
+}
+
+// EffectiveExpiryDate returns the date a product should be used by. A frozen
+// product replaces the printed expiry date with its freezer shelf life, and a
+// thawed one is further limited by the post-thaw window. An opening recorded
+// after any freeze then brings the date forward by the days-after-opening rule.
+// The days are counted from the dates of those events in loc, and the result is
+// a calendar date like the printed one.
+func (ep ExpiryPolicy) EffectiveExpiryDate(product model.Product, loc *time.Location) time.Time {
+	effective := product.ExpiryDate
+	if product.FrozenAt != nil {
+		days := ep.FreezerShelfLifeDays
+		if product.FreezerShelfLifeDays != nil {
+			days = *product.FreezerShelfLifeDays
+		}
+		effective = calendarDate(*product.FrozenAt, loc).AddDate(0, 0, days)
+		if product.ThawedAt != nil {
+			if thawed := calendarDate(*product.ThawedAt, loc).AddDate(0, 0, ep.ThawedShelfLifeDays); thawed.Before(effective) {
+				effective = thawed
+			}
+		}
+	}
+	if product.OpenedAt != nil && product.DaysAfterOpening != nil &&
+		(product.FrozenAt == nil || product.OpenedAt.After(*product.FrozenAt)) {
+		if opened := calendarDate(*product.OpenedAt, loc).AddDate(0, 0, *product.DaysAfterOpening); opened.Before(effective) {
+			effective = opened
+		}
+	}
+	return effective
+}
+
+// Evaluate computes DaysLeft and Status for product as seen from loc, based on
+// its effective expiry date.
+func (ep ExpiryPolicy) Evaluate(product model.Product, now time.Time, loc *time.Location) (int, model.ExpiryStatus) {
+	expiryDate := product.EffectiveExpiryDate
+	if expiryDate.IsZero() {
+		expiryDate = ep.EffectiveExpiryDate(product, loc)
+	}
+	daysLeft := ep.DaysLeft(expiryDate, now, loc)
+	return daysLeft, ep.Status(product.Type, daysLeft)
+}
+
+// userLocation looks up the time zone of userId.
+func userLocation(ur repository.IUserRepository, userId uint) (*time.Location, error) {
+	user := model.User{}
+	if err := ur.GetUserById(&user, userId); err != nil {
+		return nil, err
+	}
+	return user.Location(), nil
+}
+
+// calendarDate returns the date of t in loc as midnight UTC, the form expiry
+// dates are stored in.
+func calendarDate(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
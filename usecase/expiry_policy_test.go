@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"expiry_tracker/model"
+	"testing"
+	"time"
+)
+
+func TestExpiryPolicy_DaysLeft(t *testing.T) {
+	policy := NewExpiryPolicy(1, 3, 30, 1)
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	tests := []struct {
+		name       string
+		expiryDate time.Time
+		now        time.Time
+		loc        *time.Location
+		want       int
+	}{
+		{
+			name:       "当日",
+			expiryDate: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+			now:        time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+			loc:        time.UTC,
+			want:       0,
+		},
+		{
+			name:       "時刻に関わらず暦日で数える",
+			expiryDate: time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC),
+			now:        time.Date(2025, 1, 10, 23, 59, 0, 0, time.UTC),
+			loc:        time.UTC,
+			want:       1,
+		},
+		{
+			name:       "期限切れは負の値",
+			expiryDate: time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC),
+			now:        time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+			loc:        time.UTC,
+			want:       -2,
+		},
+		{
+			name:       "ユーザーのタイムゾーンで日付が変わる",
+			expiryDate: time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC),
+			now:        time.Date(2025, 1, 10, 16, 0, 0, 0, time.UTC), // 東京では1/11 01:00
+			loc:        tokyo,
+			want:       0,
+		},
+		{
+			name:       "UTC より遅れたタイムゾーンでも期限日は前日にならない",
+			expiryDate: time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC),
+			now:        time.Date(2025, 1, 11, 3, 0, 0, 0, time.UTC), // ニューヨークでは1/10 22:00
+			loc:        newYork,
+			want:       1,
+		},
+		{
+			name:       "UTC より遅れたタイムゾーンの期限当日",
+			expiryDate: time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC),
+			now:        time.Date(2025, 1, 11, 20, 0, 0, 0, time.UTC), // ニューヨークでは1/11 15:00
+			loc:        newYork,
+			want:       0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.DaysLeft(tt.expiryDate, tt.now, tt.loc); got != tt.want {
+				t.Errorf("DaysLeft() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpiryPolicy_Status(t *testing.T) {
+	policy := NewExpiryPolicy(1, 3, 30, 1)
+
+	tests := []struct {
+		name       string
+		expiryType model.ExpiryType
+		daysLeft   int
+		want       model.ExpiryStatus
+	}{
+		{name: "消費期限 期限切れ", expiryType: model.ExpiryTypeUseBy, daysLeft: -1, want: model.ExpiryStatusExpired},
+		{name: "消費期限 当日", expiryType: model.ExpiryTypeUseBy, daysLeft: 0, want: model.ExpiryStatusExpiringSoon},
+		{name: "消費期限 警告期間ちょうど", expiryType: model.ExpiryTypeUseBy, daysLeft: 1, want: model.ExpiryStatusExpiringSoon},
+		{name: "消費期限 警告期間外", expiryType: model.ExpiryTypeUseBy, daysLeft: 2, want: model.ExpiryStatusFresh},
+		{name: "賞味期限 警告期間内", expiryType: model.ExpiryTypeBestBefore, daysLeft: 2, want: model.ExpiryStatusExpiringSoon},
+		{name: "賞味期限 警告期間ちょうど", expiryType: model.ExpiryTypeBestBefore, daysLeft: 3, want: model.ExpiryStatusExpiringSoon},
+		{name: "賞味期限 警告期間外", expiryType: model.ExpiryTypeBestBefore, daysLeft: 4, want: model.ExpiryStatusFresh},
+		{name: "賞味期限 期限切れ", expiryType: model.ExpiryTypeBestBefore, daysLeft: -1, want: model.ExpiryStatusExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Status(tt.expiryType, tt.daysLeft); got != tt.want {
+				t.Errorf("Status() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpiryPolicy_EffectiveExpiryDate(t *testing.T) {
+	policy := NewExpiryPolicy(1, 3, 30, 1)
+	printed := time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)
+	openedAt := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+	days := func(n int) *int { return &n }
+	frozenAt := time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC)
+	thawedAt := time.Date(2025, 1, 20, 8, 0, 0, 0, time.UTC)
+	lateThawedAt := time.Date(2025, 2, 1, 8, 0, 0, 0, time.UTC)
+	openedAfterFreeze := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	openedAtNight := time.Date(2025, 1, 10, 20, 0, 0, 0, time.UTC) // 東京では1/11 05:00
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	date := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		product model.Product
+		loc     *time.Location
+		want    time.Time
+	}{
+		{
+			name:    "未開封",
+			product: model.Product{ExpiryDate: printed, DaysAfterOpening: days(3)},
+			want:    printed,
+		},
+		{
+			name:    "開封済みだが開封後の日数が未設定",
+			product: model.Product{ExpiryDate: printed, OpenedAt: &openedAt},
+			want:    printed,
+		},
+		{
+			name:    "開封後の期限が早い",
+			product: model.Product{ExpiryDate: printed, OpenedAt: &openedAt, DaysAfterOpening: days(3)},
+			want:    date(1, 13),
+		},
+		{
+			name:    "印字された期限が早い",
+			product: model.Product{ExpiryDate: printed, OpenedAt: &openedAt, DaysAfterOpening: days(30)},
+			want:    printed,
+		},
+		{
+			name:    "冷凍中は冷凍保存の日数で期限が決まる",
+			product: model.Product{ExpiryDate: printed, FrozenAt: &frozenAt, FreezerShelfLifeDays: days(60)},
+			want:    date(3, 16),
+		},
+		{
+			name:    "冷凍保存の日数が未設定なら既定値",
+			product: model.Product{ExpiryDate: printed, FrozenAt: &frozenAt},
+			want:    date(2, 14),
+		},
+		{
+			name:    "冷凍前の開封は冷凍中の期限に影響しない",
+			product: model.Product{ExpiryDate: printed, OpenedAt: &openedAt, DaysAfterOpening: days(3), FrozenAt: &frozenAt, FreezerShelfLifeDays: days(60)},
+			want:    date(3, 16),
+		},
+		{
+			name:    "解凍後は解凍後の日数で期限が決まる",
+			product: model.Product{ExpiryDate: printed, FrozenAt: &frozenAt, FreezerShelfLifeDays: days(60), ThawedAt: &thawedAt},
+			want:    date(1, 21),
+		},
+		{
+			name:    "解凍後の期限は冷凍保存の期限を超えない",
+			product: model.Product{ExpiryDate: printed, FrozenAt: &frozenAt, FreezerShelfLifeDays: days(7), ThawedAt: &lateThawedAt},
+			want:    date(1, 22),
+		},
+		{
+			name:    "冷凍後に開封した場合は開封後の期限も考慮",
+			product: model.Product{ExpiryDate: printed, FrozenAt: &frozenAt, FreezerShelfLifeDays: days(60), OpenedAt: &openedAfterFreeze, DaysAfterOpening: days(3)},
+			want:    date(2, 4),
+		},
+		{
+			name:    "開封日はユーザーのタイムゾーンで数える",
+			product: model.Product{ExpiryDate: printed, OpenedAt: &openedAtNight, DaysAfterOpening: days(3)},
+			loc:     tokyo,
+			want:    date(1, 14),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := tt.loc
+			if loc == nil {
+				loc = time.UTC
+			}
+			if got := policy.EffectiveExpiryDate(tt.product, loc); !got.Equal(tt.want) {
+				t.Errorf("EffectiveExpiryDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
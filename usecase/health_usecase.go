@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"expiry_tracker/buildinfo"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// IHeartbeat is implemented by background workers that report when they last
+// finished a pass.
+type IHeartbeat interface {
+	LastRun() time.Time
+	Interval() time.Duration
+}
+
+type IHealthUsecase interface {
+	Ready(ctx context.Context) model.ReadinessResponse
+	Version(ctx context.Context) model.VersionResponse
+}
+
+type healthUsecase struct {
+	hr         repository.IHealthRepository
+	heartbeats map[string]IHeartbeat
+	startedAt  time.Time
+}
+
+func NewHealthUsecase(hr repository.IHealthRepository, heartbeats map[string]IHeartbeat) IHealthUsecase {
+	return &healthUsecase{hr: hr, heartbeats: heartbeats, startedAt: time.Now()}
+}
+
+// Ready checks that the database answers, that its schema matches the
+// migrations of this build and that every worker has run recently.
+func (hu *healthUsecase) Ready(ctx context.Context) model.ReadinessResponse {
+	res := model.ReadinessResponse{Status: model.HealthStatusOK}
+	add := func(name string, err error) {
+		check := model.HealthCheck{Name: name, Status: model.HealthStatusOK}
+		if err != nil {
+			check.Status = model.HealthStatusFail
+			check.Error = err.Error()
+			res.Status = model.HealthStatusFail
+		}
+		res.Checks = append(res.Checks, check)
+	}
+
+	add("database", hu.hr.Ping(ctx))
+	_, err := hu.hr.SchemaVersion(ctx)
+	add("migrations", err)
+
+	names := make([]string, 0, len(hu.heartbeats))
+	for name := range hu.heartbeats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	now := time.Now()
+	for _, name := range names {
+		add(name, hu.checkHeartbeat(hu.heartbeats[name], now))
+	}
+	return res
+}
+
+// checkHeartbeat fails when a worker has missed two passes in a row. Before
+// its first pass the time since startup is used instead.
+func (hu *healthUsecase) checkHeartbeat(h IHeartbeat, now time.Time) error {
+	last := h.LastRun()
+	if last.IsZero() {
+		last = hu.startedAt
+	}
+	if age := now.Sub(last); age > 2*h.Interval() {
+		return fmt.Errorf("last ran %s ago", age.Round(time.Second))
+	}
+	return nil
+}
+
+func (hu *healthUsecase) Version(ctx context.Context) model.VersionResponse {
+	info := buildinfo.Get()
+	res := model.VersionResponse{
+		Version:   info.Version,
+		Commit:    info.Commit,
+		BuildTime: info.BuildTime,
+		GoVersion: info.GoVersion,
+	}
+	// 未適用のマイグレーションがあっても、適用済みのバージョンは返す
+	if version, err := hu.hr.SchemaVersion(ctx); err == nil || version > 0 {
+		res.SchemaVersion = &version
+	}
+	return res
+}
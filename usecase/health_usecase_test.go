@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"expiry_tracker/model"
+	"testing"
+	"time"
+)
+
+type fakeHealthRepository struct {
+	pingErr       error
+	schemaVersion int64
+	schemaErr     error
+}
+
+func (r *fakeHealthRepository) Ping(ctx context.Context) error {
+	return r.pingErr
+}
+
+func (r *fakeHealthRepository) SchemaVersion(ctx context.Context) (int64, error) {
+	return r.schemaVersion, r.schemaErr
+}
+
+type fakeHeartbeat struct {
+	lastRun  time.Time
+	interval time.Duration
+}
+
+func (h fakeHeartbeat) LastRun() time.Time      { return h.lastRun }
+func (h fakeHeartbeat) Interval() time.Duration { return h.interval }
+
+func TestHealthUsecase_Ready(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		repo       *fakeHealthRepository
+		heartbeat  fakeHeartbeat
+		startedAt  time.Time
+		wantStatus string
+		wantFailed []string
+	}{
+		{
+			name:       "すべて正常",
+			repo:       &fakeHealthRepository{schemaVersion: 1},
+			heartbeat:  fakeHeartbeat{lastRun: now.Add(-time.Minute), interval: time.Hour},
+			startedAt:  now,
+			wantStatus: model.HealthStatusOK,
+		},
+		{
+			name:       "データベースに接続できない",
+			repo:       &fakeHealthRepository{pingErr: errors.New("connection refused"), schemaErr: errors.New("connection refused")},
+			heartbeat:  fakeHeartbeat{lastRun: now, interval: time.Hour},
+			startedAt:  now,
+			wantStatus: model.HealthStatusFail,
+			wantFailed: []string{"database", "migrations"},
+		},
+		{
+			name:       "未適用のマイグレーションがある",
+			repo:       &fakeHealthRepository{schemaVersion: 1, schemaErr: errors.New("migration 0002_add_index is pending")},
+			heartbeat:  fakeHeartbeat{lastRun: now, interval: time.Hour},
+			startedAt:  now,
+			wantStatus: model.HealthStatusFail,
+			wantFailed: []string{"migrations"},
+		},
+		{
+			name:       "ワーカーが 2 回続けて実行されていない",
+			repo:       &fakeHealthRepository{schemaVersion: 1},
+			heartbeat:  fakeHeartbeat{lastRun: now.Add(-3 * time.Hour), interval: time.Hour},
+			startedAt:  now.Add(-24 * time.Hour),
+			wantStatus: model.HealthStatusFail,
+			wantFailed: []string{"worker"},
+		},
+		{
+			name:       "起動直後で初回の実行前",
+			repo:       &fakeHealthRepository{schemaVersion: 1},
+			heartbeat:  fakeHeartbeat{interval: time.Hour},
+			startedAt:  now.Add(-time.Minute),
+			wantStatus: model.HealthStatusOK,
+		},
+		{
+			name:       "初回の実行が終わらない",
+			repo:       &fakeHealthRepository{schemaVersion: 1},
+			heartbeat:  fakeHeartbeat{interval: time.Minute},
+			startedAt:  now.Add(-time.Hour),
+			wantStatus: model.HealthStatusFail,
+			wantFailed: []string{"worker"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hu := &healthUsecase{hr: tt.repo, heartbeats: map[string]IHeartbeat{"worker": tt.heartbeat}, startedAt: tt.startedAt}
+
+			res := hu.Ready(context.Background())
+			if res.Status != tt.wantStatus {
+				t.Errorf("Ready().Status = %s, want %s", res.Status, tt.wantStatus)
+			}
+			failed := []string{}
+			for _, check := range res.Checks {
+				if check.Status == model.HealthStatusFail {
+					failed = append(failed, check.Name)
+					if check.Error == "" {
+						t.Errorf("check %s failed without an error", check.Name)
+					}
+				}
+			}
+			if len(failed) != len(tt.wantFailed) {
+				t.Fatalf("failed checks = %v, want %v", failed, tt.wantFailed)
+			}
+			for i := range failed {
+				if failed[i] != tt.wantFailed[i] {
+					t.Errorf("failed checks = %v, want %v", failed, tt.wantFailed)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthUsecase_Version(t *testing.T) {
+	hu := NewHealthUsecase(&fakeHealthRepository{schemaVersion: 3}, nil)
+	if res := hu.Version(context.Background()); res.SchemaVersion == nil || *res.SchemaVersion != 3 {
+		t.Errorf("Version().SchemaVersion = %v, want 3", res.SchemaVersion)
+	}
+
+	hu = NewHealthUsecase(&fakeHealthRepository{schemaErr: errors.New("connection refused")}, nil)
+	res := hu.Version(context.Background())
+	if res.SchemaVersion != nil {
+		t.Errorf("Version().SchemaVersion = %v, want nil", *res.SchemaVersion)
+	}
+	if res.Version == "" || res.Commit == "" || res.BuildTime == "" {
+		t.Errorf("Version() = %+v, want build info", res)
+	}
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/mailer"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"expiry_tracker/validator"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type IHouseholdInvitationUsecase interface {
+	CreateInvitation(request model.HouseholdInvitationRequest, userId uint, householdId uint) (model.HouseholdInvitationResponse, error)
+	GetPendingInvitations(userId uint, householdId uint) ([]model.HouseholdInvitationResponse, error)
+	RevokeInvitation(userId uint, householdId uint, invitationId uint) error
+	AcceptInvitation(request model.InvitationTokenRequest, userId uint) (model.HouseholdResponse, error)
+	DeclineInvitation(request model.InvitationTokenRequest, userId uint) error
+}
+
+type householdInvitationUsecase struct {
+	ir    repository.IHouseholdInvitationRepository
+	hr    repository.IHouseholdRepository
+	ur    repository.IUserRepository
+	m     mailer.IMailer
+	iv    validator.IHouseholdInvitationValidator
+	feURL string
+	keys  TokenKeys
+}
+
+func NewHouseholdInvitationUsecase(
+	ir repository.IHouseholdInvitationRepository,
+	hr repository.IHouseholdRepository,
+	ur repository.IUserRepository,
+	m mailer.IMailer,
+	iv validator.IHouseholdInvitationValidator,
+	feURL string,
+	keys TokenKeys,
+) IHouseholdInvitationUsecase {
+	return &householdInvitationUsecase{ir: ir, hr: hr, ur: ur, m: m, iv: iv, feURL: feURL, keys: keys}
+}
+
+// CreateInvitation issues a single-use invitation token. The token is a JWT
+// carrying the invitation id and a random nonce; only a bcrypt hash of the
+// nonce is stored, so the token cannot be recovered from the database. When an
+// email address is given the invitation is also mailed to it.
+func (iu *householdInvitationUsecase) CreateInvitation(request model.HouseholdInvitationRequest, userId uint, householdId uint) (model.HouseholdInvitationResponse, error) {
+	if err := iu.iv.HouseholdInvitationValidate(request); err != nil {
+		return model.HouseholdInvitationResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(iu.hr, userId, householdId, model.HouseholdRoleOwner)
+	if err != nil {
+		return model.HouseholdInvitationResponse{}, err
+	}
+	inviter := model.User{}
+	if err := iu.ur.GetUserById(&inviter, userId); err != nil {
+		return model.HouseholdInvitationResponse{}, err
+	}
+
+	nonce, err := randomSecret()
+	if err != nil {
+		return model.HouseholdInvitationResponse{}, err
+	}
+	hash, err := hashSecret(nonce)
+	if err != nil {
+		return model.HouseholdInvitationResponse{}, err
+	}
+	expiresInDays := request.ExpiresInDays
+	if expiresInDays == 0 {
+		expiresInDays = model.DefaultInvitationExpiresInDays
+	}
+	invitation := model.HouseholdInvitation{
+		HouseholdId: member.HouseholdId,
+		Household:   member.Household,
+		InviterId:   userId,
+		Inviter:     inviter,
+		Email:       strings.ToLower(strings.TrimSpace(request.Email)),
+		Role:        request.Role,
+		TokenHash:   hash,
+		ExpiresAt:   time.Now().AddDate(0, 0, expiresInDays),
+	}
+	if err := iu.ir.CreateInvitation(&invitation); err != nil {
+		return model.HouseholdInvitationResponse{}, err
+	}
+
+	token, err := iu.keys.signToken(tokenPurposeHouseholdInvitation, jwt.MapClaims{
+		"invitation_id": invitation.ID,
+		"nonce":         nonce,
+		"exp":           invitation.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return model.HouseholdInvitationResponse{}, err
+	}
+	resInvitation := toHouseholdInvitationResponse(invitation)
+	resInvitation.Token = token
+	resInvitation.URL = iu.feURL + "/invitations?token=" + url.QueryEscape(token)
+
+	if invitation.Email != "" {
+		subject := fmt.Sprintf("%s さんから「%s」への招待が届いています", inviter.Name, invitation.Household.Name)
+		body := fmt.Sprintf("以下のリンクから招待を承諾できます（%s まで有効）。\n\n%s\n",
+			invitation.ExpiresAt.Format("2006-01-02 15:04"), resInvitation.URL)
+		if err := iu.m.Send(invitation.Email, subject, body); err != nil {
+			// 届かない招待を残さない
+			if revokeErr := iu.ir.RevokeInvitation(invitation.HouseholdId, invitation.ID, time.Now()); revokeErr != nil {
+				return model.HouseholdInvitationResponse{}, errors.Join(err, revokeErr)
+			}
+			return model.HouseholdInvitationResponse{}, err
+		}
+	}
+
+	return resInvitation, nil
+}
+
+func (iu *householdInvitationUsecase) GetPendingInvitations(userId uint, householdId uint) ([]model.HouseholdInvitationResponse, error) {
+	member, err := authorizeHousehold(iu.hr, userId, householdId, model.HouseholdRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := []model.HouseholdInvitation{}
+	if err := iu.ir.GetPendingInvitations(&invitations, member.HouseholdId, time.Now()); err != nil {
+		return nil, err
+	}
+
+	resInvitations := []model.HouseholdInvitationResponse{}
+	for _, v := range invitations {
+		resInvitations = append(resInvitations, toHouseholdInvitationResponse(v))
+	}
+	return resInvitations, nil
+}
+
+func (iu *householdInvitationUsecase) RevokeInvitation(userId uint, householdId uint, invitationId uint) error {
+	member, err := authorizeHousehold(iu.hr, userId, householdId, model.HouseholdRoleOwner)
+	if err != nil {
+		return err
+	}
+	if err := iu.ir.RevokeInvitation(member.HouseholdId, invitationId, time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (iu *householdInvitationUsecase) AcceptInvitation(request model.InvitationTokenRequest, userId uint) (model.HouseholdResponse, error) {
+	invitation, err := iu.invitationFor(request, userId)
+	if err != nil {
+		return model.HouseholdResponse{}, err
+	}
+	if err := iu.ir.AcceptInvitation(invitation.ID, userId, time.Now()); err != nil {
+		return model.HouseholdResponse{}, err
+	}
+	return toHouseholdResponse(invitation.Household, invitation.Role), nil
+}
+
+func (iu *householdInvitationUsecase) DeclineInvitation(request model.InvitationTokenRequest, userId uint) error {
+	invitation, err := iu.invitationFor(request, userId)
+	if err != nil {
+		return err
+	}
+	if err := iu.ir.DeclineInvitation(invitation.ID, time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// invitationFor verifies the token and returns the invitation it refers to,
+// provided userId may respond to it. An invitation sent to an email address
+// can only be answered by a user who has verified that address. Used, revoked
+// and expired invitations are rejected by the repository when the response is
+// recorded.
+func (iu *householdInvitationUsecase) invitationFor(request model.InvitationTokenRequest, userId uint) (model.HouseholdInvitation, error) {
+	if err := iu.iv.InvitationTokenValidate(request); err != nil {
+		return model.HouseholdInvitation{}, apperror.Validation(err)
+	}
+	claims, err := iu.keys.parseToken(tokenPurposeHouseholdInvitation, request.Token)
+	if err != nil {
+		return model.HouseholdInvitation{}, err
+	}
+	invitationId, err := uintClaim(claims, "invitation_id")
+	if err != nil {
+		return model.HouseholdInvitation{}, err
+	}
+	nonce, _ := claims["nonce"].(string)
+
+	invitation := model.HouseholdInvitation{}
+	if err := iu.ir.GetInvitationById(&invitation, invitationId); err != nil {
+		return model.HouseholdInvitation{}, err
+	}
+	if !compareSecret(invitation.TokenHash, nonce) {
+		return model.HouseholdInvitation{}, apperror.Invalid("invalid or expired token")
+	}
+	if invitation.Email != "" {
+		user := model.User{}
+		if err := iu.ur.GetUserById(&user, userId); err != nil {
+			return model.HouseholdInvitation{}, err
+		}
+		if !strings.EqualFold(user.Email, invitation.Email) {
+			return model.HouseholdInvitation{}, apperror.Forbidden("invitation is for another email address")
+		}
+		// 確認していないアドレスでは、そのアドレスの持ち主であることを示せない
+		if user.EmailVerifiedAt == nil {
+			return model.HouseholdInvitation{}, apperror.Forbidden("verify your email address to respond to this invitation")
+		}
+	}
+	return invitation, nil
+}
+
+func toHouseholdInvitationResponse(invitation model.HouseholdInvitation) model.HouseholdInvitationResponse {
+	return model.HouseholdInvitationResponse{
+		ID:            invitation.ID,
+		HouseholdId:   invitation.HouseholdId,
+		HouseholdName: invitation.Household.Name,
+		InviterName:   invitation.Inviter.Name,
+		Email:         invitation.Email,
+		Role:          invitation.Role,
+		ExpiresAt:     invitation.ExpiresAt,
+		CreatedAt:     invitation.CreatedAt,
+	}
+}
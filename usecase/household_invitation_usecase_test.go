@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"expiry_tracker/validator"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeHouseholdInvitationRepository は 1 件の招待を返し、承諾を記録する
+type fakeHouseholdInvitationRepository struct {
+	repository.IHouseholdInvitationRepository
+	invitation model.HouseholdInvitation
+	acceptedBy []uint
+}
+
+func (r *fakeHouseholdInvitationRepository) GetInvitationById(invitation *model.HouseholdInvitation, invitationId uint) error {
+	if invitationId != r.invitation.ID {
+		return apperror.NotFound("invitation not found")
+	}
+	*invitation = r.invitation
+	return nil
+}
+
+func (r *fakeHouseholdInvitationRepository) AcceptInvitation(invitationId uint, userId uint, now time.Time) error {
+	r.acceptedBy = append(r.acceptedBy, userId)
+	return nil
+}
+
+func TestHouseholdInvitationUsecase_AcceptInvitation(t *testing.T) {
+	keys := NewTokenKeys("0123456789abcdef0123456789abcdef")
+	nonce := "nonce"
+	hash, err := hashSecret(nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := keys.signToken(tokenPurposeHouseholdInvitation, jwt.MapClaims{
+		"invitation_id": 1,
+		"nonce":         nonce,
+		"exp":           time.Now().Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifiedAt := time.Now()
+	users := map[uint]model.User{
+		1: {ID: 1, Email: "invitee@example.com", EmailVerifiedAt: &verifiedAt},
+		2: {ID: 2, Email: "invitee@example.com"},
+		3: {ID: 3, Email: "other@example.com", EmailVerifiedAt: &verifiedAt},
+	}
+
+	tests := []struct {
+		name     string
+		email    string
+		userId   uint
+		wantKind apperror.Kind
+	}{
+		{name: "確認済みのアドレス", email: "invitee@example.com", userId: 1},
+		{name: "未確認のアドレス", email: "invitee@example.com", userId: 2, wantKind: apperror.KindForbidden},
+		{name: "別のアドレス", email: "invitee@example.com", userId: 3, wantKind: apperror.KindForbidden},
+		{name: "アドレスを指定しない招待は未確認でも承諾できる", userId: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ir := &fakeHouseholdInvitationRepository{invitation: model.HouseholdInvitation{
+				ID:        1,
+				Email:     tt.email,
+				Role:      model.HouseholdRoleEditor,
+				TokenHash: hash,
+			}}
+			iu := NewHouseholdInvitationUsecase(ir, nil, &fakeUserRepository{users: users}, nil,
+				validator.NewHouseholdInvitationValidator(), "", keys)
+
+			_, err := iu.AcceptInvitation(model.InvitationTokenRequest{Token: token}, tt.userId)
+			if tt.wantKind != "" {
+				if apperror.KindOf(err) != tt.wantKind {
+					t.Errorf("AcceptInvitation() error = %v, want %s", err, tt.wantKind)
+				}
+				if len(ir.acceptedBy) != 0 {
+					t.Errorf("AcceptInvitation() accepted by %v, want no member added", ir.acceptedBy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AcceptInvitation() error = %v", err)
+			}
+			if len(ir.acceptedBy) != 1 || ir.acceptedBy[0] != tt.userId {
+				t.Errorf("AcceptInvitation() accepted by %v, want user %d", ir.acceptedBy, tt.userId)
+			}
+		})
+	}
+}
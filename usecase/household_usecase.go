@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"expiry_tracker/validator"
+)
+
+type IHouseholdUsecase interface {
+	GetAllHouseholds(userId uint) ([]model.HouseholdResponse, error)
+	GetHouseholdById(userId uint, householdId uint) (model.HouseholdResponse, error)
+	CreateHousehold(household model.Household, userId uint) (model.HouseholdResponse, error)
+	UpdateHousehold(household model.Household, userId uint, householdId uint) (model.HouseholdResponse, error)
+	DeleteHousehold(userId uint, householdId uint) error
+	UpdateMemberRole(request model.HouseholdMemberRoleRequest, userId uint, householdId uint, memberId uint) (model.HouseholdResponse, error)
+	RemoveMember(userId uint, householdId uint, memberId uint) error
+}
+
+type householdUsecase struct {
+	hr repository.IHouseholdRepository
+	hv validator.IHouseholdValidator
+}
+
+func NewHouseholdUsecase(hr repository.IHouseholdRepository, hv validator.IHouseholdValidator) IHouseholdUsecase {
+	return &householdUsecase{hr: hr, hv: hv}
+}
+
+func (hu *householdUsecase) GetAllHouseholds(userId uint) ([]model.HouseholdResponse, error) {
+	members := []model.HouseholdMember{}
+	if err := hu.hr.GetMembershipsByUserId(&members, userId); err != nil {
+		return nil, err
+	}
+
+	resHouseholds := []model.HouseholdResponse{}
+	for _, v := range members {
+		resHouseholds = append(resHouseholds, toHouseholdResponse(v.Household, v.Role))
+	}
+	return resHouseholds, nil
+}
+
+func (hu *householdUsecase) GetHouseholdById(userId uint, householdId uint) (model.HouseholdResponse, error) {
+	member, err := authorizeHousehold(hu.hr, userId, householdId, model.HouseholdRoleViewer)
+	if err != nil {
+		return model.HouseholdResponse{}, err
+	}
+
+	members := []model.HouseholdMember{}
+	if err := hu.hr.GetMembers(&members, member.HouseholdId); err != nil {
+		return model.HouseholdResponse{}, err
+	}
+
+	resHousehold := toHouseholdResponse(member.Household, member.Role)
+	for _, v := range members {
+		resHousehold.Members = append(resHousehold.Members, model.HouseholdMemberResponse{
+			UserId:   v.UserId,
+			Name:     v.User.Name,
+			Email:    v.User.Email,
+			Role:     v.Role,
+			JoinedAt: v.CreatedAt,
+		})
+	}
+	return resHousehold, nil
+}
+
+func (hu *householdUsecase) CreateHousehold(household model.Household, userId uint) (model.HouseholdResponse, error) {
+	if err := hu.hv.HouseholdValidate(household); err != nil {
+		return model.HouseholdResponse{}, apperror.Validation(err)
+	}
+	if err := hu.hr.CreateHousehold(&household, userId); err != nil {
+		return model.HouseholdResponse{}, err
+	}
+	return toHouseholdResponse(household, model.HouseholdRoleOwner), nil
+}
+
+func (hu *householdUsecase) UpdateHousehold(household model.Household, userId uint, householdId uint) (model.HouseholdResponse, error) {
+	if err := hu.hv.HouseholdValidate(household); err != nil {
+		return model.HouseholdResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(hu.hr, userId, householdId, model.HouseholdRoleOwner)
+	if err != nil {
+		return model.HouseholdResponse{}, err
+	}
+	if err := hu.hr.UpdateHousehold(&household, member.HouseholdId); err != nil {
+		return model.HouseholdResponse{}, err
+	}
+	return toHouseholdResponse(household, member.Role), nil
+}
+
+func (hu *householdUsecase) DeleteHousehold(userId uint, householdId uint) error {
+	member, err := authorizeHousehold(hu.hr, userId, householdId, model.HouseholdRoleOwner)
+	if err != nil {
+		return err
+	}
+	if err := hu.hr.DeleteHousehold(member.HouseholdId); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (hu *householdUsecase) UpdateMemberRole(request model.HouseholdMemberRoleRequest, userId uint, householdId uint, memberId uint) (model.HouseholdResponse, error) {
+	if err := hu.hv.HouseholdMemberRoleValidate(request); err != nil {
+		return model.HouseholdResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(hu.hr, userId, householdId, model.HouseholdRoleOwner)
+	if err != nil {
+		return model.HouseholdResponse{}, err
+	}
+	if err := hu.hr.UpdateMemberRole(member.HouseholdId, memberId, request.Role); err != nil {
+		return model.HouseholdResponse{}, err
+	}
+	return hu.GetHouseholdById(userId, member.HouseholdId)
+}
+
+// RemoveMember removes memberId from the household. Owners may remove anyone;
+// other members may only remove themselves, i.e. leave the household.
+func (hu *householdUsecase) RemoveMember(userId uint, householdId uint, memberId uint) error {
+	required := model.HouseholdRoleOwner
+	if memberId == userId {
+		required = model.HouseholdRoleViewer
+	}
+	member, err := authorizeHousehold(hu.hr, userId, householdId, required)
+	if err != nil {
+		return err
+	}
+	if err := hu.hr.DeleteMember(member.HouseholdId, memberId); err != nil {
+		return err
+	}
+	return nil
+}
+
+// authorizeHousehold returns userId's membership of householdId, or of the
+// user's default household when householdId is 0, and checks that it grants
+// required. Households the user is not a member of are reported as not found,
+// as is the default household of a user who has left or deleted every one.
+func authorizeHousehold(hr repository.IHouseholdRepository, userId uint, householdId uint, required model.HouseholdRole) (model.HouseholdMember, error) {
+	member := model.HouseholdMember{}
+	if householdId != 0 {
+		if err := hr.GetMember(&member, householdId, userId); err != nil {
+			return model.HouseholdMember{}, err
+		}
+	} else {
+		members := []model.HouseholdMember{}
+		if err := hr.GetMembershipsByUserId(&members, userId); err != nil {
+			return model.HouseholdMember{}, err
+		}
+		// 個人用の世帯は登録時とマイグレーションで作成するため、ここでは作らない
+		if len(members) == 0 {
+			return model.HouseholdMember{}, apperror.NotFound("household not found")
+		}
+		member = members[0]
+	}
+
+	if !member.Role.Allows(required) {
+		return model.HouseholdMember{}, apperror.Forbidden("insufficient household role")
+	}
+	return member, nil
+}
+
+func toHouseholdResponse(household model.Household, role model.HouseholdRole) model.HouseholdResponse {
+	return model.HouseholdResponse{
+		ID:        household.ID,
+		Name:      household.Name,
+		Role:      role,
+		CreatedAt: household.CreatedAt,
+		UpdatedAt: household.UpdatedAt,
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"testing"
+)
+
+func TestAuthorizeHousehold(t *testing.T) {
+	// CreateHousehold は実装していないため、世帯を作成しようとすると panic する
+	hr := &fakeHouseholdRepository{members: []model.HouseholdMember{
+		{HouseholdId: 1, UserId: 1, Role: model.HouseholdRoleOwner},
+		{HouseholdId: 2, UserId: 1, Role: model.HouseholdRoleViewer},
+		{HouseholdId: 2, UserId: 2, Role: model.HouseholdRoleOwner},
+	}}
+
+	tests := []struct {
+		name          string
+		userId        uint
+		householdId   uint
+		required      model.HouseholdRole
+		wantHousehold uint
+		wantKind      apperror.Kind
+	}{
+		{
+			name:          "省略時は最初に所属した世帯",
+			userId:        1,
+			required:      model.HouseholdRoleOwner,
+			wantHousehold: 1,
+		},
+		{
+			name:          "指定した世帯",
+			userId:        1,
+			householdId:   2,
+			required:      model.HouseholdRoleViewer,
+			wantHousehold: 2,
+		},
+		{
+			name:        "役割が足りない",
+			userId:      1,
+			householdId: 2,
+			required:    model.HouseholdRoleEditor,
+			wantKind:    apperror.KindForbidden,
+		},
+		{
+			name:        "所属していない世帯",
+			userId:      2,
+			householdId: 1,
+			required:    model.HouseholdRoleViewer,
+			wantKind:    apperror.KindNotFound,
+		},
+		{
+			name:     "どの世帯にも所属していない場合は作成しない",
+			userId:   3,
+			required: model.HouseholdRoleViewer,
+			wantKind: apperror.KindNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			member, err := authorizeHousehold(hr, tt.userId, tt.householdId, tt.required)
+			if tt.wantKind != "" {
+				if apperror.KindOf(err) != tt.wantKind {
+					t.Errorf("authorizeHousehold() error = %v, want kind %v", err, tt.wantKind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("authorizeHousehold() error = %v", err)
+			}
+			if member.HouseholdId != tt.wantHousehold {
+				t.Errorf("authorizeHousehold() household = %d, want %d", member.HouseholdId, tt.wantHousehold)
+			}
+		})
+	}
+}
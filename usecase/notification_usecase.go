@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"errors"
+	"expiry_tracker/model"
+	"expiry_tracker/notifier"
+	"expiry_tracker/repository"
+	"time"
+)
+
+type INotificationUsecase interface {
+	NotifyExpiringProducts() error
+}
+
+// INotificationMetrics receives counts from the notification pipeline.
+type INotificationMetrics interface {
+	ExpiringProducts(n int)
+	NotificationQueued()
+	NotificationSent()
+	NotificationFailed()
+}
+
+type notificationUsecase struct {
+	pr repository.IProductRepository
+	hr repository.IHouseholdRepository
+	nr repository.IExpiryNotificationRepository
+	n  notifier.INotifier
+	ep ExpiryPolicy
+	nm INotificationMetrics
+}
+
+func NewNotificationUsecase(pr repository.IProductRepository, hr repository.IHouseholdRepository, nr repository.IExpiryNotificationRepository, n notifier.INotifier, ep ExpiryPolicy, nm INotificationMetrics) INotificationUsecase {
+	return &notificationUsecase{pr: pr, hr: hr, nr: nr, n: n, ep: ep, nm: nm}
+}
+
+// pendingProduct tracks the deliveries of one candidate product in a run.
+type pendingProduct struct {
+	product     model.Product
+	recipients  int  // 通知の対象になったメンバーの数
+	outstanding int  // 送信を確保して結果を待っている通知の数
+	incomplete  bool // 送信に失敗した、他のレプリカが送信中、またはまだ期限が近くないメンバーがいる
+}
+
+// NotifyExpiringProducts sends every member of a household with a verified
+// email address the products that are no longer fresh as seen from the
+// member's time zone. Each delivery is claimed per product, user and expiry
+// date, so a member is never sent the same notification twice and a failed
+// send is retried for that member only. A product leaves the candidates once
+// every verified member has been sent its notification; members in time zones
+// where it is still fresh keep it a candidate until their turn comes.
+func (nu *notificationUsecase) NotifyExpiringProducts() error {
+	products := []model.Product{}
+	now := time.Now()
+	// タイムゾーンの差を吸収するため1日余分に取得し、判定はExpiryPolicyに任せる
+	until := now.AddDate(0, 0, nu.ep.MaxWarningDays()+1)
+	if err := nu.pr.GetNotificationCandidates(&products, until); err != nil {
+		return err
+	}
+
+	households := map[uint][]model.HouseholdMember{}
+	users := map[uint]model.User{}
+	claimed := map[uint][]model.ExpiryNotification{}
+	pending := map[uint]*pendingProduct{}
+	expiring := 0
+	for _, v := range products {
+		members, ok := households[v.HouseholdId]
+		if !ok {
+			if err := nu.hr.GetMembers(&members, v.HouseholdId); err != nil {
+				return err
+			}
+			households[v.HouseholdId] = members
+		}
+
+		p := &pendingProduct{product: v}
+		for _, m := range members {
+			// 他人のアドレスに送らないよう、確認済みのアドレスにだけ通知する
+			if m.User.EmailVerifiedAt == nil {
+				continue
+			}
+			if _, status := nu.ep.Evaluate(v, now, m.User.Location()); status == model.ExpiryStatusFresh {
+				p.incomplete = true
+				continue
+			}
+			p.recipients++
+
+			notification := model.ExpiryNotification{ProductId: v.ID, UserId: m.UserId, ExpiryDate: v.EffectiveExpiryDate}
+			ok, sent, err := nu.nr.ClaimNotification(&notification, now, model.ExpiryNotificationClaimTimeout)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				// 送信済みなら何もしない。未送信なら他のレプリカが送信中
+				p.incomplete = p.incomplete || !sent
+				continue
+			}
+			users[m.UserId] = m.User
+			claimed[m.UserId] = append(claimed[m.UserId], notification)
+			p.outstanding++
+		}
+		if p.outstanding > 0 {
+			expiring++
+		}
+		pending[v.ID] = p
+	}
+
+	nu.nm.ExpiringProducts(expiring)
+
+	var errs []error
+	for userId, notifications := range claimed {
+		ids := make([]uint, 0, len(notifications))
+		userProducts := make([]model.Product, 0, len(notifications))
+		for _, n := range notifications {
+			ids = append(ids, n.ID)
+			userProducts = append(userProducts, pending[n.ProductId].product)
+		}
+
+		nu.nm.NotificationQueued()
+		if err := nu.n.NotifyExpiringProducts(users[userId], userProducts); err != nil {
+			nu.nm.NotificationFailed()
+			errs = append(errs, err)
+			// 送信に失敗したこのユーザーの分だけ、次回の実行で再送できるようにする
+			if err := nu.nr.ReleaseNotifications(ids); err != nil {
+				errs = append(errs, err)
+			}
+			for _, n := range notifications {
+				pending[n.ProductId].incomplete = true
+			}
+			continue
+		}
+		nu.nm.NotificationSent()
+		if err := nu.nr.MarkSent(ids, time.Now()); err != nil {
+			// 送信済みにできなかった通知は、確保の期限が切れた後に再送される
+			errs = append(errs, err)
+			for _, n := range notifications {
+				pending[n.ProductId].incomplete = true
+			}
+			continue
+		}
+		for _, n := range notifications {
+			pending[n.ProductId].outstanding--
+		}
+	}
+
+	for _, p := range pending {
+		// 通知の対象がいない製品は、期限が近づくかアドレスが確認されるまで候補に残す
+		if p.recipients == 0 || p.incomplete || p.outstanding > 0 {
+			continue
+		}
+		if err := nu.pr.MarkNotified(p.product.ID, p.product.EffectiveExpiryDate); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
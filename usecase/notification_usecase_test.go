@@ -0,0 +1,411 @@
+package usecase
+
+import (
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"expiry_tracker/validator"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeProductRepository は製品の登録・更新と通知に使うメソッドだけを実装する
+type fakeProductRepository struct {
+	repository.IProductRepository
+	products map[uint]*model.Product
+}
+
+func (r *fakeProductRepository) GetProductById(product *model.Product, householdId uint, productId uint) error {
+	p, ok := r.products[productId]
+	if !ok || p.HouseholdId != householdId {
+		return apperror.NotFound("product not found")
+	}
+	*product = *p
+	return nil
+}
+
+func (r *fakeProductRepository) CreateProduct(product *model.Product) error {
+	product.ID = uint(len(r.products) + 1)
+	stored := *product
+	r.products[product.ID] = &stored
+	return nil
+}
+
+func (r *fakeProductRepository) UpdateProduct(product *model.Product, householdId uint, productId uint) error {
+	p, ok := r.products[productId]
+	if !ok || p.HouseholdId != householdId {
+		return apperror.NotFound("product not found")
+	}
+	p.Name = product.Name
+	p.Quantity = product.Quantity
+	p.ExpiryDate = product.ExpiryDate
+	p.Type = product.Type
+	p.EffectiveExpiryDate = product.EffectiveExpiryDate
+	p.IsNotified = product.IsNotified
+	return nil
+}
+
+func (r *fakeProductRepository) GetNotificationCandidates(products *[]model.Product, until time.Time) error {
+	for _, p := range r.products {
+		if !p.IsNotified && p.ArchivedAt == nil && !p.EffectiveExpiryDate.After(until) {
+			*products = append(*products, *p)
+		}
+	}
+	return nil
+}
+
+func (r *fakeProductRepository) MarkNotified(productId uint, expiryDate time.Time) error {
+	if p := r.products[productId]; p.EffectiveExpiryDate.Equal(expiryDate) {
+		p.IsNotified = true
+	}
+	return nil
+}
+
+// fakeExpiryNotificationRepository は製品・ユーザー・期限ごとの通知をメモリに保存する
+type fakeExpiryNotificationRepository struct {
+	notifications []*model.ExpiryNotification
+}
+
+func (r *fakeExpiryNotificationRepository) find(n *model.ExpiryNotification) *model.ExpiryNotification {
+	for _, v := range r.notifications {
+		if v.ProductId == n.ProductId && v.UserId == n.UserId && v.ExpiryDate.Equal(n.ExpiryDate) {
+			return v
+		}
+	}
+	return nil
+}
+
+func (r *fakeExpiryNotificationRepository) ClaimNotification(notification *model.ExpiryNotification, now time.Time, claimTimeout time.Duration) (bool, bool, error) {
+	existing := r.find(notification)
+	if existing == nil {
+		notification.ID = uint(len(r.notifications) + 1)
+		notification.ClaimedAt = now
+		stored := *notification
+		r.notifications = append(r.notifications, &stored)
+		return true, false, nil
+	}
+	if existing.SentAt != nil || !existing.ClaimedAt.Before(now.Add(-claimTimeout)) {
+		return false, existing.SentAt != nil, nil
+	}
+	existing.ClaimedAt = now
+	*notification = *existing
+	return true, false, nil
+}
+
+func (r *fakeExpiryNotificationRepository) MarkSent(ids []uint, now time.Time) error {
+	for _, v := range r.notifications {
+		if slices.Contains(ids, v.ID) {
+			v.SentAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeExpiryNotificationRepository) ReleaseNotifications(ids []uint) error {
+	r.notifications = slices.DeleteFunc(r.notifications, func(v *model.ExpiryNotification) bool {
+		return slices.Contains(ids, v.ID) && v.SentAt == nil
+	})
+	return nil
+}
+
+type fakeHouseholdRepository struct {
+	repository.IHouseholdRepository
+	members []model.HouseholdMember
+}
+
+func (r *fakeHouseholdRepository) GetMember(member *model.HouseholdMember, householdId uint, userId uint) error {
+	for _, m := range r.members {
+		if m.HouseholdId == householdId && m.UserId == userId {
+			*member = m
+			return nil
+		}
+	}
+	return apperror.NotFound("household not found")
+}
+
+func (r *fakeHouseholdRepository) GetMembershipsByUserId(members *[]model.HouseholdMember, userId uint) error {
+	for _, m := range r.members {
+		if m.UserId == userId {
+			*members = append(*members, m)
+		}
+	}
+	return nil
+}
+
+func (r *fakeHouseholdRepository) GetMembers(members *[]model.HouseholdMember, householdId uint) error {
+	for _, m := range r.members {
+		if m.HouseholdId == householdId {
+			*members = append(*members, m)
+		}
+	}
+	return nil
+}
+
+type fakeUserRepository struct {
+	repository.IUserRepository
+	users map[uint]model.User
+}
+
+func (r *fakeUserRepository) GetUserById(user *model.User, userId uint) error {
+	u, ok := r.users[userId]
+	if !ok {
+		return apperror.NotFound("user not found")
+	}
+	*user = u
+	return nil
+}
+
+// fakeNotifier は送信内容を記録し、failures が残っている間と failing のユーザーへの送信に失敗する
+type fakeNotifier struct {
+	failures int
+	failing  map[uint]bool
+	sent     map[uint][]uint
+}
+
+func (n *fakeNotifier) NotifyExpiringProducts(user model.User, products []model.Product) error {
+	if n.failing[user.ID] {
+		return errors.New("mailbox unavailable")
+	}
+	if n.failures > 0 {
+		n.failures--
+		return errors.New("smtp unavailable")
+	}
+	if n.sent == nil {
+		n.sent = map[uint][]uint{}
+	}
+	for _, p := range products {
+		n.sent[user.ID] = append(n.sent[user.ID], p.ID)
+	}
+	return nil
+}
+
+type fakeNotificationMetrics struct {
+	queued, sent, failed int
+}
+
+func (m *fakeNotificationMetrics) ExpiringProducts(n int) {}
+func (m *fakeNotificationMetrics) NotificationQueued()    { m.queued++ }
+func (m *fakeNotificationMetrics) NotificationSent()      { m.sent++ }
+func (m *fakeNotificationMetrics) NotificationFailed()    { m.failed++ }
+
+type notificationFixture struct {
+	products      *fakeProductRepository
+	notifications *fakeExpiryNotificationRepository
+	households    *fakeHouseholdRepository
+	notifier      *fakeNotifier
+	metrics       *fakeNotificationMetrics
+	nu            INotificationUsecase
+	pu            IProductUsecase
+}
+
+// newNotificationFixture は確認済みのユーザー 1 と未確認のユーザー 2 が所属する世帯に、
+// 明日が期限の製品 1 と 30 日後が期限の製品 2 を登録する
+func newNotificationFixture(failures int) notificationFixture {
+	now := time.Now()
+	tomorrow := calendarDate(now, time.UTC).AddDate(0, 0, 1)
+	later := calendarDate(now, time.UTC).AddDate(0, 0, 30)
+	verified := now.AddDate(0, 0, -1)
+	users := map[uint]model.User{
+		1: {ID: 1, Email: "owner@example.com", TimeZone: "UTC", EmailVerifiedAt: &verified},
+		2: {ID: 2, Email: "viewer@example.com", TimeZone: "UTC"},
+	}
+	products := &fakeProductRepository{products: map[uint]*model.Product{
+		1: {ID: 1, HouseholdId: 1, UserId: 1, Name: "牛乳", Quantity: 1, Type: model.ExpiryTypeUseBy, ExpiryDate: tomorrow, EffectiveExpiryDate: tomorrow},
+		2: {ID: 2, HouseholdId: 1, UserId: 1, Name: "缶詰", Quantity: 1, Type: model.ExpiryTypeBestBefore, ExpiryDate: later, EffectiveExpiryDate: later},
+	}}
+	households := &fakeHouseholdRepository{members: []model.HouseholdMember{
+		{HouseholdId: 1, UserId: 1, User: users[1], Role: model.HouseholdRoleOwner},
+		{HouseholdId: 1, UserId: 2, User: users[2], Role: model.HouseholdRoleViewer},
+	}}
+	notifications := &fakeExpiryNotificationRepository{}
+	notifier := &fakeNotifier{failures: failures}
+	metrics := &fakeNotificationMetrics{}
+	policy := NewExpiryPolicy(1, 3, 30, 1)
+
+	return notificationFixture{
+		products:      products,
+		notifications: notifications,
+		households:    households,
+		notifier:      notifier,
+		metrics:       metrics,
+		nu:            NewNotificationUsecase(products, households, notifications, notifier, policy, metrics),
+		pu:            NewProductUsecase(products, &fakeUserRepository{users: users}, nil, nil, households, validator.NewProductValidator(), policy),
+	}
+}
+
+func TestNotificationUsecase_NotifyExpiringProducts(t *testing.T) {
+	t.Run("期限が近い製品は確認済みのメンバーに1回だけ通知する", func(t *testing.T) {
+		f := newNotificationFixture(0)
+
+		for i := 0; i < 2; i++ {
+			if err := f.nu.NotifyExpiringProducts(); err != nil {
+				t.Fatalf("NotifyExpiringProducts() error = %v", err)
+			}
+		}
+
+		if got := f.notifier.sent[1]; len(got) != 1 || got[0] != 1 {
+			t.Errorf("sent to user 1 = %v, want [1]", got)
+		}
+		if got := f.notifier.sent[2]; len(got) != 0 {
+			t.Errorf("sent to unverified user 2 = %v, want none", got)
+		}
+		if f.products.products[2].IsNotified {
+			t.Error("fresh product 2 was claimed")
+		}
+		if f.metrics.sent != 1 || f.metrics.failed != 0 {
+			t.Errorf("metrics sent = %d, failed = %d, want 1, 0", f.metrics.sent, f.metrics.failed)
+		}
+	})
+
+	t.Run("送信に失敗した場合は通知済みにせず次回に再送する", func(t *testing.T) {
+		f := newNotificationFixture(1)
+
+		if err := f.nu.NotifyExpiringProducts(); err == nil {
+			t.Fatal("NotifyExpiringProducts() error = nil, want the send error")
+		}
+		if f.products.products[1].IsNotified {
+			t.Fatal("product 1 stays claimed after a failed send")
+		}
+
+		if err := f.nu.NotifyExpiringProducts(); err != nil {
+			t.Fatalf("NotifyExpiringProducts() error = %v", err)
+		}
+		if got := f.notifier.sent[1]; len(got) != 1 || got[0] != 1 {
+			t.Errorf("sent to user 1 = %v, want [1]", got)
+		}
+		if f.metrics.sent != 1 || f.metrics.failed != 1 {
+			t.Errorf("metrics sent = %d, failed = %d, want 1, 1", f.metrics.sent, f.metrics.failed)
+		}
+	})
+
+	t.Run("期限を変更すると再び通知する", func(t *testing.T) {
+		f := newNotificationFixture(0)
+		if err := f.nu.NotifyExpiringProducts(); err != nil {
+			t.Fatalf("NotifyExpiringProducts() error = %v", err)
+		}
+
+		// 期限を変えない編集では再通知しない
+		product := *f.products.products[1]
+		product.Name = "低脂肪乳"
+		if _, err := f.pu.UpdateProduct(product, 1, 1, 1); err != nil {
+			t.Fatalf("UpdateProduct() error = %v", err)
+		}
+		if err := f.nu.NotifyExpiringProducts(); err != nil {
+			t.Fatalf("NotifyExpiringProducts() error = %v", err)
+		}
+		if got := f.notifier.sent[1]; len(got) != 1 {
+			t.Fatalf("sent to user 1 = %v, want one notification before the expiry date changes", got)
+		}
+
+		product.ExpiryDate = product.ExpiryDate.AddDate(0, 0, -1)
+		if _, err := f.pu.UpdateProduct(product, 1, 1, 1); err != nil {
+			t.Fatalf("UpdateProduct() error = %v", err)
+		}
+		if f.products.products[1].IsNotified {
+			t.Fatal("product 1 stays notified after its expiry date changed")
+		}
+		if err := f.nu.NotifyExpiringProducts(); err != nil {
+			t.Fatalf("NotifyExpiringProducts() error = %v", err)
+		}
+		if got := f.notifier.sent[1]; len(got) != 2 {
+			t.Errorf("sent to user 1 = %v, want a second notification", got)
+		}
+	})
+
+	t.Run("一部のメンバーへの送信に失敗しても送信済みのメンバーには再送しない", func(t *testing.T) {
+		f := newNotificationFixture(0)
+		verified := time.Now()
+		f.households.members = append(f.households.members, model.HouseholdMember{
+			HouseholdId: 1, UserId: 3, Role: model.HouseholdRoleEditor,
+			User: model.User{ID: 3, Email: "editor@example.com", TimeZone: "UTC", EmailVerifiedAt: &verified},
+		})
+		f.notifier.failing = map[uint]bool{3: true}
+
+		if err := f.nu.NotifyExpiringProducts(); err == nil {
+			t.Fatal("NotifyExpiringProducts() error = nil, want the send error")
+		}
+		if f.products.products[1].IsNotified {
+			t.Fatal("product 1 was marked notified before every member received it")
+		}
+
+		f.notifier.failing = nil
+		if err := f.nu.NotifyExpiringProducts(); err != nil {
+			t.Fatalf("NotifyExpiringProducts() error = %v", err)
+		}
+		if got := f.notifier.sent[1]; len(got) != 1 {
+			t.Errorf("sent to user 1 = %v, want exactly one notification", got)
+		}
+		if got := f.notifier.sent[3]; len(got) != 1 || got[0] != 1 {
+			t.Errorf("sent to user 3 = %v, want [1]", got)
+		}
+		if !f.products.products[1].IsNotified {
+			t.Error("product 1 was not marked notified after every member received it")
+		}
+	})
+
+	t.Run("まだ期限が近くないメンバーがいる間は製品を候補に残す", func(t *testing.T) {
+		f := newNotificationFixture(0)
+		kiritimati, _ := time.LoadLocation("Pacific/Kiritimati")
+		verified := time.Now()
+		f.households.members = append(f.households.members, model.HouseholdMember{
+			HouseholdId: 1, UserId: 3, Role: model.HouseholdRoleEditor,
+			User: model.User{ID: 3, Email: "editor@example.com", TimeZone: "Pacific/Kiritimati", EmailVerifiedAt: &verified},
+		})
+		// UTC+14 では明日が期限だが、UTC ではまだ 2 日以上ある
+		product := f.products.products[1]
+		product.ExpiryDate = calendarDate(time.Now(), kiritimati).AddDate(0, 0, 1)
+		product.EffectiveExpiryDate = product.ExpiryDate
+
+		if err := f.nu.NotifyExpiringProducts(); err != nil {
+			t.Fatalf("NotifyExpiringProducts() error = %v", err)
+		}
+		if got := f.notifier.sent[3]; len(got) != 1 || got[0] != 1 {
+			t.Errorf("sent to user 3 = %v, want [1]", got)
+		}
+		if got := f.notifier.sent[1]; len(got) != 0 {
+			t.Errorf("sent to user 1 = %v, want none while the product is fresh for them", got)
+		}
+		if product.IsNotified {
+			t.Error("product 1 was marked notified before user 1 received it")
+		}
+	})
+
+	t.Run("送信前に停止した通知は確保の期限が切れた後に送る", func(t *testing.T) {
+		f := newNotificationFixture(0)
+		product := f.products.products[1]
+		f.notifications.notifications = append(f.notifications.notifications, &model.ExpiryNotification{
+			ID: 1, ProductId: 1, UserId: 1, ExpiryDate: product.EffectiveExpiryDate,
+			ClaimedAt: time.Now().Add(-model.ExpiryNotificationClaimTimeout - time.Minute),
+		})
+
+		if err := f.nu.NotifyExpiringProducts(); err != nil {
+			t.Fatalf("NotifyExpiringProducts() error = %v", err)
+		}
+		if got := f.notifier.sent[1]; len(got) != 1 || got[0] != 1 {
+			t.Errorf("sent to user 1 = %v, want [1]", got)
+		}
+		if !product.IsNotified {
+			t.Error("product 1 was not marked notified after the retry")
+		}
+	})
+
+	t.Run("他のレプリカが送信中の通知は送らず製品を候補に残す", func(t *testing.T) {
+		f := newNotificationFixture(0)
+		product := f.products.products[1]
+		f.notifications.notifications = append(f.notifications.notifications, &model.ExpiryNotification{
+			ID: 1, ProductId: 1, UserId: 1, ExpiryDate: product.EffectiveExpiryDate, ClaimedAt: time.Now(),
+		})
+
+		if err := f.nu.NotifyExpiringProducts(); err != nil {
+			t.Fatalf("NotifyExpiringProducts() error = %v", err)
+		}
+		if got := f.notifier.sent[1]; len(got) != 0 {
+			t.Errorf("sent to user 1 = %v, want none", got)
+		}
+		if product.IsNotified {
+			t.Error("product 1 was marked notified while its delivery is in flight")
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"expiry_tracker/sso"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type IOidcUsecase interface {
+	AuthCodeURL(ctx context.Context) (string, string, error)
+	Callback(ctx context.Context, code string, state string, stateToken string, client model.SessionClient) (model.LoginResult, error)
+}
+
+type oidcUsecase struct {
+	p    sso.IProvider
+	ur   repository.IUserRepository
+	ir   repository.IUserIdentityRepository
+	sr   repository.ISessionRepository
+	keys TokenKeys
+}
+
+func NewOidcUsecase(
+	p sso.IProvider,
+	ur repository.IUserRepository,
+	ir repository.IUserIdentityRepository,
+	sr repository.ISessionRepository,
+	keys TokenKeys,
+) IOidcUsecase {
+	return &oidcUsecase{p: p, ur: ur, ir: ir, sr: sr, keys: keys}
+}
+
+// AuthCodeURL starts a login with the identity provider. It returns the URL
+// to redirect the user to and a state token that the browser must present on
+// the callback; the token binds the callback to this browser and carries the
+// nonce and the PKCE code verifier.
+func (ou *oidcUsecase) AuthCodeURL(ctx context.Context) (string, string, error) {
+	state, err := randomSecret()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomSecret()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := randomSecret()
+	if err != nil {
+		return "", "", err
+	}
+	stateToken, err := ou.keys.signToken(tokenPurposeOidcState, jwt.MapClaims{
+		"state":    state,
+		"nonce":    nonce,
+		"verifier": verifier,
+		"exp":      time.Now().Add(model.OidcStateLifetime).Unix(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	authURL, err := ou.p.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, stateToken, nil
+}
+
+// Callback completes the login. The identity is looked up by issuer and
+// subject; an identity seen for the first time is linked to the user with
+// the same email address, provided the provider has verified it, or a new
+// user is created.
+func (ou *oidcUsecase) Callback(ctx context.Context, code string, state string, stateToken string, client model.SessionClient) (model.LoginResult, error) {
+	claims, err := ou.keys.parseToken(tokenPurposeOidcState, stateToken)
+	if err != nil {
+		return model.LoginResult{}, apperror.Unauthorized("invalid or expired login state")
+	}
+	expectedState, _ := claims["state"].(string)
+	nonce, _ := claims["nonce"].(string)
+	verifier, _ := claims["verifier"].(string)
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(expectedState)) != 1 {
+		return model.LoginResult{}, apperror.Unauthorized("invalid or expired login state")
+	}
+
+	identity, err := ou.p.Exchange(ctx, code, verifier, nonce)
+	if err != nil {
+		if errors.Is(err, sso.ErrInvalidResponse) {
+			return model.LoginResult{}, apperror.Unauthorized("identity provider login failed")
+		}
+		return model.LoginResult{}, err
+	}
+
+	user, err := ou.userFor(identity)
+	if err != nil {
+		return model.LoginResult{}, err
+	}
+	return completeLogin(ou.keys, ou.sr, user, client)
+}
+
+func (ou *oidcUsecase) userFor(identity sso.Identity) (model.User, error) {
+	user := model.User{}
+	linked := model.UserIdentity{}
+	err := ou.ir.GetIdentity(&linked, identity.Issuer, identity.Subject)
+	if err == nil {
+		if err := ou.ur.GetUserById(&user, linked.UserId); err != nil {
+			return model.User{}, err
+		}
+		return user, nil
+	}
+	if apperror.KindOf(err) != apperror.KindNotFound {
+		return model.User{}, err
+	}
+
+	// 未確認のアドレスで紐付けると他人のアカウントを乗っ取れてしまう
+	if identity.Email == "" || !identity.EmailVerified {
+		return model.User{}, apperror.Forbidden("identity provider did not return a verified email address")
+	}
+	err = ou.ur.GetUserByEmail(&user, identity.Email)
+	switch {
+	case err == nil:
+		// パスワードで登録した未確認のアカウントは、アドレスの持ち主以外が作った可能性がある
+		if user.EmailVerifiedAt == nil {
+			return model.User{}, apperror.Conflict("verify your email address before signing in with the identity provider")
+		}
+	case apperror.KindOf(err) == apperror.KindNotFound:
+		if user, err = ou.createUser(identity); err != nil {
+			return model.User{}, err
+		}
+	default:
+		return model.User{}, err
+	}
+
+	linked = model.UserIdentity{
+		UserId:  user.ID,
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+		Email:   identity.Email,
+	}
+	if err := ou.ir.CreateIdentity(&linked); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// createUser registers a user who signs in with the identity provider for the
+// first time. The user has no password until they set one with a password
+// reset.
+func (ou *oidcUsecase) createUser(identity sso.Identity) (model.User, error) {
+	name := strings.TrimSpace(identity.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+	if r := []rune(name); len(r) > 30 {
+		name = string(r[:30])
+	}
+	now := time.Now()
+	user := model.User{
+		Email:           identity.Email,
+		Name:            name,
+		TimeZone:        model.DefaultTimeZone,
+		EmailVerifiedAt: &now,
+	}
+	household := model.Household{Name: model.DefaultHouseholdName}
+	if err := ou.ur.CreateUser(&user, &household); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/mailer"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"expiry_tracker/validator"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type IPasswordResetUsecase interface {
+	ForgotPassword(request model.ForgotPasswordRequest) error
+	ResetPassword(request model.ResetPasswordRequest) error
+}
+
+type passwordResetUsecase struct {
+	pr    repository.IPasswordResetRepository
+	ur    repository.IUserRepository
+	m     mailer.IMailer
+	pv    validator.IPasswordResetValidator
+	feURL string
+	keys  TokenKeys
+}
+
+func NewPasswordResetUsecase(
+	pr repository.IPasswordResetRepository,
+	ur repository.IUserRepository,
+	m mailer.IMailer,
+	pv validator.IPasswordResetValidator,
+	feURL string,
+	keys TokenKeys,
+) IPasswordResetUsecase {
+	return &passwordResetUsecase{pr: pr, ur: ur, m: m, pv: pv, feURL: feURL, keys: keys}
+}
+
+// ForgotPassword mails a password reset link to the user with the given
+// email address. Unknown addresses are ignored without an error, so the
+// response does not reveal which addresses are registered.
+func (pu *passwordResetUsecase) ForgotPassword(request model.ForgotPasswordRequest) error {
+	if err := pu.pv.ForgotPasswordValidate(request); err != nil {
+		return apperror.Validation(err)
+	}
+	user := model.User{}
+	if err := pu.ur.GetUserByEmail(&user, request.Email); err != nil {
+		if apperror.KindOf(err) == apperror.KindNotFound {
+			return nil
+		}
+		return err
+	}
+
+	nonce, err := randomSecret()
+	if err != nil {
+		return err
+	}
+	hash, err := hashSecret(nonce)
+	if err != nil {
+		return err
+	}
+	reset := model.PasswordReset{
+		UserId:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(model.PasswordResetLifetime),
+	}
+	if err := pu.pr.CreatePasswordReset(&reset); err != nil {
+		return err
+	}
+
+	token, err := pu.keys.signToken(tokenPurposePasswordReset, jwt.MapClaims{
+		"reset_id": reset.ID,
+		"nonce":    nonce,
+		"exp":      reset.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	link := pu.feURL + "/reset-password?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("%s さん\n\n以下のリンクからパスワードを再設定してください（%s まで有効）。\n\n%s\n\n"+
+		"このメールに心当たりがない場合は破棄してください。パスワードは変更されません。\n",
+		user.Name, reset.ExpiresAt.Format("2006-01-02 15:04"), link)
+	return pu.m.Send(user.Email, "パスワードの再設定", body)
+}
+
+// ResetPassword sets a new password using a token issued by ForgotPassword.
+// Every session of the user is revoked, so they have to log in again.
+func (pu *passwordResetUsecase) ResetPassword(request model.ResetPasswordRequest) error {
+	if err := pu.pv.ResetPasswordValidate(request); err != nil {
+		return apperror.Validation(err)
+	}
+	claims, err := pu.keys.parseToken(tokenPurposePasswordReset, request.Token)
+	if err != nil {
+		return err
+	}
+	resetId, err := uintClaim(claims, "reset_id")
+	if err != nil {
+		return err
+	}
+	nonce, _ := claims["nonce"].(string)
+
+	reset := model.PasswordReset{}
+	if err := pu.pr.GetPasswordResetById(&reset, resetId); err != nil {
+		if apperror.KindOf(err) == apperror.KindNotFound {
+			return apperror.Invalid("invalid or expired token")
+		}
+		return err
+	}
+	if !compareSecret(reset.TokenHash, nonce) {
+		return apperror.Invalid("invalid or expired token")
+	}
+
+	hash, err := hashSecret(request.Password)
+	if err != nil {
+		return err
+	}
+	if err := pu.pr.ResetPassword(reset.ID, hash, time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
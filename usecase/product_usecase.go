@@ -1,125 +1,596 @@
 package usecase
 
 import (
+	"expiry_tracker/apperror"
 	"expiry_tracker/model"
 	"expiry_tracker/repository"
 	"expiry_tracker/validator"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 type IProductUsecase interface {
-	GetAllProducts(userId uint) ([]model.ProductResponse, error)
-	GetProductByID(userId uint, productId uint) (model.ProductResponse, error)
-	CreateProduct(product model.Product) (model.ProductResponse, error)
-	UpdateProduct(product model.Product, userId uint, productId uint) (model.ProductResponse, error)
-	DeleteProduct(userId uint, productId uint) error
+	GetAllProducts(userId uint, householdId uint, query model.ProductQuery) (model.ProductListResponse, error)
+	GetProductByID(userId uint, householdId uint, productId uint) (model.ProductResponse, error)
+	CreateProduct(product model.Product, householdId uint) (model.ProductResponse, error)
+	UpdateProduct(product model.Product, userId uint, householdId uint, productId uint) (model.ProductResponse, error)
+	DeleteProduct(userId uint, householdId uint, productId uint) error
+	ConsumeProduct(adjustment model.QuantityAdjustment, userId uint, householdId uint, productId uint) (model.ProductResponse, error)
+	DiscardProduct(adjustment model.QuantityAdjustment, userId uint, householdId uint, productId uint) (model.ProductResponse, error)
+	GetConsumptionEvents(userId uint, householdId uint, productId uint) ([]model.ConsumptionEventResponse, error)
+	OpenProduct(request model.OpenProductRequest, userId uint, householdId uint, productId uint) (model.ProductResponse, error)
+	FreezeProduct(request model.FreezeProductRequest, userId uint, householdId uint, productId uint) (model.ProductResponse, error)
+	ThawProduct(request model.ThawProductRequest, userId uint, householdId uint, productId uint) (model.ProductResponse, error)
 }
 
 type productUsecase struct {
 	pr repository.IProductRepository
+	ur repository.IUserRepository
+	cr repository.ICategoryRepository
+	lr repository.IStorageLocationRepository
+	hr repository.IHouseholdRepository
 	uv validator.IProductValidator
+	ep ExpiryPolicy
 }
 
-func NewProductUsecase(pr repository.IProductRepository, uv validator.IProductValidator) IProductUsecase {
-	return &productUsecase{pr: pr, uv: uv}
+func NewProductUsecase(
+	pr repository.IProductRepository,
+	ur repository.IUserRepository,
+	cr repository.ICategoryRepository,
+	lr repository.IStorageLocationRepository,
+	hr repository.IHouseholdRepository,
+	uv validator.IProductValidator,
+	ep ExpiryPolicy,
+) IProductUsecase {
+	return &productUsecase{pr: pr, ur: ur, cr: cr, lr: lr, hr: hr, uv: uv, ep: ep}
 }
 
-func (pu *productUsecase) GetAllProducts(userId uint) ([]model.ProductResponse, error) {
+func (pu *productUsecase) GetAllProducts(userId uint, householdId uint, query model.ProductQuery) (model.ProductListResponse, error) {
+	if err := pu.uv.ProductQueryValidate(query); err != nil {
+		return model.ProductListResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(pu.hr, userId, householdId, model.HouseholdRoleViewer)
+	if err != nil {
+		return model.ProductListResponse{}, err
+	}
+	loc, err := userLocation(pu.ur, userId)
+	if err != nil {
+		return model.ProductListResponse{}, err
+	}
+
+	now := time.Now()
+	page := max(query.Page, 1)
+	perPage := query.PerPage
+	if perPage == 0 {
+		perPage = model.DefaultProductPerPage
+	}
+	filter := repository.ProductFilter{
+		Type:     query.Type,
+		Name:     query.Q,
+		Archived: query.Archived,
+
+		CategoryId:        query.CategoryId,
+		StorageLocationId: query.StorageLocationId,
+		Sort:              query.Sort,
+		Desc:              query.Order == model.SortOrderDesc,
+		Limit:             perPage,
+		Offset:            (page - 1) * perPage,
+	}
+	filter.ExpiryFrom, filter.ExpiryBefore = expiryBounds(query.ExpiryFrom, query.ExpiryTo)
+	if query.Status != "" {
+		filter.ExpiryRanges = pu.statusRanges(query.Status, now, loc)
+	}
+
 	products := []model.Product{}
-	if err := pu.pr.GetAllProducts(&products, userId); err != nil {
-		return nil, err
+	var total int64
+	if err := pu.pr.GetAllProducts(&products, &total, member.HouseholdId, filter); err != nil {
+		return model.ProductListResponse{}, err
 	}
 
 	resProducts := []model.ProductResponse{}
-
 	for _, v := range products {
-		p := model.ProductResponse{
-			ID:          v.ID,
-			Name:        v.Name,
-			Description: v.Description,
-			Quantity:    v.Quantity,
-			ExpiryDate:  v.ExpiryDate,
-			Type:        v.Type,
-			IsNotified:  v.IsNotified,
-			CreatedAt:   v.CreatedAt,
-			UpdatedAt:   v.UpdatedAt,
-		}
-		resProducts = append(resProducts, p)
+		resProducts = append(resProducts, pu.toProductResponse(v, now, loc))
 	}
 
-	return resProducts, nil
+	return model.ProductListResponse{
+		Items:      resProducts,
+		Total:      total,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: int((total + int64(perPage) - 1) / int64(perPage)),
+	}, nil
 }
 
-func (pu *productUsecase) GetProductByID(userId uint, productId uint) (model.ProductResponse, error) {
-	product := model.Product{}
+func (pu *productUsecase) GetProductByID(userId uint, householdId uint, productId uint) (model.ProductResponse, error) {
+	member, err := authorizeHousehold(pu.hr, userId, householdId, model.HouseholdRoleViewer)
+	if err != nil {
+		return model.ProductResponse{}, err
+	}
+	return pu.productResponse(userId, member.HouseholdId, productId)
+}
 
-	if err := pu.pr.GetProductById(&product, userId, productId); err != nil {
+// productResponse loads a product of an already authorized household as seen
+// by userId.
+func (pu *productUsecase) productResponse(userId uint, householdId uint, productId uint) (model.ProductResponse, error) {
+	loc, err := userLocation(pu.ur, userId)
+	if err != nil {
 		return model.ProductResponse{}, err
 	}
 
-	resProduct := model.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		Quantity:    product.Quantity,
-		ExpiryDate:  product.ExpiryDate,
-		Type:        product.Type,
-		IsNotified:  product.IsNotified,
-		CreatedAt:   product.CreatedAt,
-		UpdatedAt:   product.UpdatedAt,
+	product := model.Product{}
+	if err := pu.pr.GetProductById(&product, householdId, productId); err != nil {
+		return model.ProductResponse{}, err
 	}
 
-	return resProduct, nil
+	return pu.toProductResponse(product, time.Now(), loc), nil
 }
 
-func (pu *productUsecase) CreateProduct(product model.Product) (model.ProductResponse, error) {
+func (pu *productUsecase) CreateProduct(product model.Product, householdId uint) (model.ProductResponse, error) {
+	// 開封・冷凍・解凍・消費や通知の状態はそれぞれの専用エンドポイントと通知処理でのみ変更する
+	product = model.Product{
+		UserId:            product.UserId,
+		Name:              product.Name,
+		Description:       product.Description,
+		Quantity:          product.Quantity,
+		ExpiryDate:        product.ExpiryDate,
+		Type:              product.Type,
+		DaysAfterOpening:  product.DaysAfterOpening,
+		CategoryId:        product.CategoryId,
+		StorageLocationId: product.StorageLocationId,
+	}
 	if err := pu.uv.ProductValidate(product); err != nil {
+		return model.ProductResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(pu.hr, product.UserId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
+		return model.ProductResponse{}, err
+	}
+	if err := pu.validateReferences(product, member.HouseholdId); err != nil {
+		return model.ProductResponse{}, err
+	}
+	loc, err := userLocation(pu.ur, product.UserId)
+	if err != nil {
 		return model.ProductResponse{}, err
 	}
+	product.HouseholdId = member.HouseholdId
+	product.EffectiveExpiryDate = pu.ep.EffectiveExpiryDate(product, loc)
 	if err := pu.pr.CreateProduct(&product); err != nil {
 		return model.ProductResponse{}, err
 	}
 
-	resProduct := model.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		Quantity:    product.Quantity,
-		ExpiryDate:  product.ExpiryDate,
-		Type:        product.Type,
-		IsNotified:  product.IsNotified,
-		CreatedAt:   product.CreatedAt,
-		UpdatedAt:   product.UpdatedAt,
+	return pu.productResponse(product.UserId, member.HouseholdId, product.ID)
+}
+
+func (pu *productUsecase) UpdateProduct(product model.Product, userId uint, householdId uint, productId uint) (model.ProductResponse, error) {
+	if err := pu.uv.ProductValidate(product); err != nil {
+		return model.ProductResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(pu.hr, userId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
+		return model.ProductResponse{}, err
+	}
+	if err := pu.validateReferences(product, member.HouseholdId); err != nil {
+		return model.ProductResponse{}, err
+	}
+
+	current := model.Product{}
+	if err := pu.pr.GetProductById(&current, member.HouseholdId, productId); err != nil {
+		return model.ProductResponse{}, err
+	}
+	// 開封・冷凍・解凍の状態はそれぞれの専用エンドポイントでのみ変更する
+	product.OpenedAt = current.OpenedAt
+	product.FrozenAt = current.FrozenAt
+	product.ThawedAt = current.ThawedAt
+	product.FreezerShelfLifeDays = current.FreezerShelfLifeDays
+	if err := pu.applyEffectiveExpiry(&product, current, userId); err != nil {
+		return model.ProductResponse{}, err
 	}
 
-	return resProduct, nil
+	if err := pu.pr.UpdateProduct(&product, member.HouseholdId, productId); err != nil {
+		return model.ProductResponse{}, err
+	}
+
+	return pu.productResponse(userId, member.HouseholdId, productId)
 }
 
-func (pu *productUsecase) UpdateProduct(product model.Product, userId uint, productId uint) (model.ProductResponse, error) {
-	if err := pu.uv.ProductValidate(product); err != nil {
+// OpenProduct marks a product as opened, which may bring its effective expiry
+// date forward.
+func (pu *productUsecase) OpenProduct(request model.OpenProductRequest, userId uint, householdId uint, productId uint) (model.ProductResponse, error) {
+	if err := pu.uv.OpenProductValidate(request); err != nil {
+		return model.ProductResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(pu.hr, userId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
 		return model.ProductResponse{}, err
 	}
-	if err := pu.pr.UpdateProduct(&product, userId, productId); err != nil {
+
+	product := model.Product{}
+	if err := pu.pr.GetProductById(&product, member.HouseholdId, productId); err != nil {
 		return model.ProductResponse{}, err
 	}
+	if product.ArchivedAt != nil {
+		return model.ProductResponse{}, apperror.Conflict("product is already used up")
+	}
 
-	resProduct := model.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		Quantity:    product.Quantity,
-		ExpiryDate:  product.ExpiryDate,
-		Type:        product.Type,
-		IsNotified:  product.IsNotified,
-		UpdatedAt:   product.UpdatedAt,
+	current := product
+	openedAt := time.Now()
+	if request.OpenedAt != nil {
+		openedAt = *request.OpenedAt
+	}
+	product.OpenedAt = &openedAt
+	if request.DaysAfterOpening != nil {
+		product.DaysAfterOpening = request.DaysAfterOpening
+	}
+	if err := pu.applyEffectiveExpiry(&product, current, userId); err != nil {
+		return model.ProductResponse{}, err
 	}
 
-	return resProduct, nil
+	if err := pu.pr.UpdateProductFields(&product, member.HouseholdId, productId, "opened_at", "days_after_opening", "effective_expiry_date", "is_notified"); err != nil {
+		return model.ProductResponse{}, err
+	}
+
+	return pu.productResponse(userId, member.HouseholdId, productId)
 }
 
-func (pu *productUsecase) DeleteProduct(userId uint, productId uint) error {
-	if err := pu.pr.DeleteProduct(userId, productId); err != nil {
+// FreezeProduct moves a product into a freezer. Its effective expiry date is
+// replaced by the freezer shelf life of its category, fixed at freeze time.
+func (pu *productUsecase) FreezeProduct(request model.FreezeProductRequest, userId uint, householdId uint, productId uint) (model.ProductResponse, error) {
+	if err := pu.uv.FreezeProductValidate(request); err != nil {
+		return model.ProductResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(pu.hr, userId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
+		return model.ProductResponse{}, err
+	}
+
+	product := model.Product{}
+	if err := pu.pr.GetProductById(&product, member.HouseholdId, productId); err != nil {
+		return model.ProductResponse{}, err
+	}
+	switch {
+	case product.ArchivedAt != nil:
+		return model.ProductResponse{}, apperror.Conflict("product is already used up")
+	case product.ThawedAt != nil:
+		return model.ProductResponse{}, apperror.Conflict("thawed product cannot be refrozen")
+	case product.FrozenAt != nil:
+		return model.ProductResponse{}, apperror.Conflict("product is already frozen")
+	}
+
+	location, err := pu.storageLocationFor(request.StorageLocationId, member.HouseholdId, model.StorageLocationKindFreezer)
+	if err != nil {
+		return model.ProductResponse{}, err
+	}
+	if location == nil {
+		return model.ProductResponse{}, apperror.Validation(validation.Errors{
+			"storage_location_id": validation.NewError("validation_required", "no freezer location registered"),
+		})
+	}
+
+	current := product
+	frozenAt := time.Now()
+	if request.FrozenAt != nil {
+		frozenAt = *request.FrozenAt
+	}
+	days := pu.ep.FreezerShelfLife(product.Category)
+	if request.FreezerShelfLifeDays != nil {
+		days = *request.FreezerShelfLifeDays
+	}
+	product.FrozenAt = &frozenAt
+	product.FreezerShelfLifeDays = &days
+	product.StorageLocationId = &location.ID
+	if err := pu.applyEffectiveExpiry(&product, current, userId); err != nil {
+		return model.ProductResponse{}, err
+	}
+
+	if err := pu.pr.UpdateProductFields(&product, member.HouseholdId, productId, "frozen_at", "freezer_shelf_life_days", "storage_location_id", "effective_expiry_date", "is_notified"); err != nil {
+		return model.ProductResponse{}, err
+	}
+
+	return pu.productResponse(userId, member.HouseholdId, productId)
+}
+
+// ThawProduct takes a frozen product out of the freezer, leaving it the
+// post-thaw window to be used in.
+func (pu *productUsecase) ThawProduct(request model.ThawProductRequest, userId uint, householdId uint, productId uint) (model.ProductResponse, error) {
+	if err := pu.uv.ThawProductValidate(request); err != nil {
+		return model.ProductResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(pu.hr, userId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
+		return model.ProductResponse{}, err
+	}
+
+	product := model.Product{}
+	if err := pu.pr.GetProductById(&product, member.HouseholdId, productId); err != nil {
+		return model.ProductResponse{}, err
+	}
+	switch {
+	case product.ArchivedAt != nil:
+		return model.ProductResponse{}, apperror.Conflict("product is already used up")
+	case product.FrozenAt == nil || product.ThawedAt != nil:
+		return model.ProductResponse{}, apperror.Conflict("product is not frozen")
+	}
+
+	thawedAt := time.Now()
+	if request.ThawedAt != nil {
+		thawedAt = *request.ThawedAt
+	}
+	if thawedAt.Before(*product.FrozenAt) {
+		return model.ProductResponse{}, apperror.Validation(validation.Errors{
+			"thawed_at": validation.NewError("validation_min_greater_equal_than_required", "thawed_at must not be before frozen_at"),
+		})
+	}
+
+	location, err := pu.storageLocationFor(request.StorageLocationId, member.HouseholdId, model.StorageLocationKindFridge)
+	if err != nil {
+		return model.ProductResponse{}, err
+	}
+
+	current := product
+	product.ThawedAt = &thawedAt
+	product.StorageLocationId = nil
+	if location != nil {
+		product.StorageLocationId = &location.ID
+	}
+	if err := pu.applyEffectiveExpiry(&product, current, userId); err != nil {
+		return model.ProductResponse{}, err
+	}
+
+	if err := pu.pr.UpdateProductFields(&product, member.HouseholdId, productId, "thawed_at", "storage_location_id", "effective_expiry_date", "is_notified"); err != nil {
+		return model.ProductResponse{}, err
+	}
+
+	return pu.productResponse(userId, member.HouseholdId, productId)
+}
+
+// storageLocationFor returns the requested storage location, or the household's
+// first location of the given kind when none is requested. It returns nil when
+// the user has no such location. A requested location must be a freezer
+// exactly when kind is freezer.
+func (pu *productUsecase) storageLocationFor(locationId *uint, householdId uint, kind model.StorageLocationKind) (*model.StorageLocation, error) {
+	if locationId == nil {
+		locations := []model.StorageLocation{}
+		if err := pu.lr.GetAllStorageLocations(&locations, householdId); err != nil {
+			return nil, err
+		}
+		for _, v := range locations {
+			if v.Kind == kind {
+				return &v, nil
+			}
+		}
+		return nil, nil
+	}
+
+	location := model.StorageLocation{}
+	if err := pu.lr.GetStorageLocationById(&location, householdId, *locationId); err != nil {
+		if apperror.KindOf(err) != apperror.KindNotFound {
+			return nil, err
+		}
+		return nil, apperror.Validation(validation.Errors{
+			"storage_location_id": validation.NewError("validation_not_found", "storage location not found"),
+		})
+	}
+	isFreezer := location.Kind == model.StorageLocationKindFreezer
+	if kind == model.StorageLocationKindFreezer && !isFreezer {
+		return nil, apperror.Validation(validation.Errors{
+			"storage_location_id": validation.NewError("validation_not_freezer", "storage location is not a freezer"),
+		})
+	}
+	if kind != model.StorageLocationKindFreezer && isFreezer {
+		return nil, apperror.Validation(validation.Errors{
+			"storage_location_id": validation.NewError("validation_freezer", "storage location must not be a freezer"),
+		})
+	}
+	return &location, nil
+}
+
+// applyEffectiveExpiry recomputes the effective expiry date of product, as
+// recorded by userId, and re-arms the expiry notification if that date moved.
+func (pu *productUsecase) applyEffectiveExpiry(product *model.Product, current model.Product, userId uint) error {
+	loc, err := userLocation(pu.ur, userId)
+	if err != nil {
 		return err
 	}
+	product.EffectiveExpiryDate = pu.ep.EffectiveExpiryDate(*product, loc)
+	product.IsNotified = current.IsNotified && product.EffectiveExpiryDate.Equal(current.EffectiveExpiryDate)
+	return nil
+}
+
+func (pu *productUsecase) DeleteProduct(userId uint, householdId uint, productId uint) error {
+	member, err := authorizeHousehold(pu.hr, userId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := pu.pr.DeleteProduct(member.HouseholdId, productId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ConsumeProduct records that part of a product was eaten. Amount defaults to 1.
+func (pu *productUsecase) ConsumeProduct(adjustment model.QuantityAdjustment, userId uint, householdId uint, productId uint) (model.ProductResponse, error) {
+	return pu.adjustQuantity(model.ConsumptionEventConsumed, adjustment, userId, householdId, productId)
+}
+
+// DiscardProduct records that part of a product was thrown away. Amount
+// defaults to the whole remaining quantity.
+func (pu *productUsecase) DiscardProduct(adjustment model.QuantityAdjustment, userId uint, householdId uint, productId uint) (model.ProductResponse, error) {
+	return pu.adjustQuantity(model.ConsumptionEventWasted, adjustment, userId, householdId, productId)
+}
+
+func (pu *productUsecase) adjustQuantity(kind model.ConsumptionEventKind, adjustment model.QuantityAdjustment, userId uint, householdId uint, productId uint) (model.ProductResponse, error) {
+	if err := pu.uv.QuantityAdjustmentValidate(adjustment); err != nil {
+		return model.ProductResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(pu.hr, userId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
+		return model.ProductResponse{}, err
+	}
+
+	product := model.Product{}
+	if err := pu.pr.GetProductById(&product, member.HouseholdId, productId); err != nil {
+		return model.ProductResponse{}, err
+	}
+	if product.ArchivedAt != nil {
+		return model.ProductResponse{}, apperror.Conflict("product is already used up")
+	}
+
+	amount := adjustment.Amount
+	if amount == 0 {
+		amount = 1
+		if kind == model.ConsumptionEventWasted {
+			amount = product.Quantity
+		}
+	}
+	if amount > product.Quantity {
+		return model.ProductResponse{}, apperror.Invalid("amount exceeds remaining quantity")
+	}
+
+	event := model.ConsumptionEvent{
+		UserId:     userId,
+		Kind:       kind,
+		Amount:     amount,
+		OccurredAt: time.Now(),
+	}
+	updated := model.Product{}
+	if err := pu.pr.DecrementQuantity(&updated, &event, member.HouseholdId, productId); err != nil {
+		return model.ProductResponse{}, err
+	}
+
+	return pu.productResponse(userId, member.HouseholdId, productId)
+}
+
+func (pu *productUsecase) GetConsumptionEvents(userId uint, householdId uint, productId uint) ([]model.ConsumptionEventResponse, error) {
+	member, err := authorizeHousehold(pu.hr, userId, householdId, model.HouseholdRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	product := model.Product{}
+	if err := pu.pr.GetProductById(&product, member.HouseholdId, productId); err != nil {
+		return nil, err
+	}
+
+	events := []model.ConsumptionEvent{}
+	if err := pu.pr.GetConsumptionEvents(&events, member.HouseholdId, productId); err != nil {
+		return nil, err
+	}
+
+	resEvents := []model.ConsumptionEventResponse{}
+	for _, v := range events {
+		resEvents = append(resEvents, model.ConsumptionEventResponse{
+			ID:         v.ID,
+			ProductId:  v.ProductId,
+			Kind:       v.Kind,
+			Amount:     v.Amount,
+			OccurredAt: v.OccurredAt,
+		})
+	}
 
+	return resEvents, nil
+}
+
+// validateReferences ensures the category and storage location set on product
+// belong to the household, reporting violations as field errors.
+func (pu *productUsecase) validateReferences(product model.Product, householdId uint) error {
+	errs := validation.Errors{}
+	if product.CategoryId != nil {
+		category := model.Category{}
+		if err := pu.cr.GetCategoryById(&category, householdId, *product.CategoryId); err != nil {
+			if apperror.KindOf(err) != apperror.KindNotFound {
+				return err
+			}
+			errs["category_id"] = validation.NewError("validation_not_found", "category not found")
+		}
+	}
+	if product.StorageLocationId != nil {
+		location := model.StorageLocation{}
+		if err := pu.lr.GetStorageLocationById(&location, householdId, *product.StorageLocationId); err != nil {
+			if apperror.KindOf(err) != apperror.KindNotFound {
+				return err
+			}
+			errs["storage_location_id"] = validation.NewError("validation_not_found", "storage location not found")
+		}
+	}
+	if len(errs) > 0 {
+		return apperror.Validation(errs)
+	}
 	return nil
 }
+
+// expiryBounds translates the inclusive expiry_from and expiry_to dates into a
+// half-open range of stored expiry dates. Either bound may be empty.
+func expiryBounds(expiryFrom string, expiryTo string) (*time.Time, *time.Time) {
+	var from, before *time.Time
+	if expiryFrom != "" {
+		d, _ := time.Parse("2006-01-02", expiryFrom)
+		from = &d
+	}
+	if expiryTo != "" {
+		d, _ := time.Parse("2006-01-02", expiryTo)
+		d = d.AddDate(0, 0, 1)
+		before = &d
+	}
+	return from, before
+}
+
+// statusRanges translates an expiry status into per-type expiry date ranges,
+// using the same calendar-day thresholds as ExpiryPolicy.
+func (pu *productUsecase) statusRanges(status model.ExpiryStatus, now time.Time, loc *time.Location) []repository.ExpiryRange {
+	today := calendarDate(now, loc)
+
+	ranges := []repository.ExpiryRange{}
+	for _, t := range []model.ExpiryType{model.ExpiryTypeUseBy, model.ExpiryTypeBestBefore} {
+		soonEnd := today.AddDate(0, 0, pu.ep.WarningDays(t)+1)
+		r := repository.ExpiryRange{Type: t}
+		switch status {
+		case model.ExpiryStatusExpired:
+			r.Before = &today
+		case model.ExpiryStatusExpiringSoon:
+			r.From = &today
+			r.Before = &soonEnd
+		case model.ExpiryStatusFresh:
+			r.From = &soonEnd
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+func (pu *productUsecase) toProductResponse(product model.Product, now time.Time, loc *time.Location) model.ProductResponse {
+	daysLeft, status := pu.ep.Evaluate(product, now, loc)
+	var category *model.CategoryResponse
+	if product.Category != nil {
+		res := toCategoryResponse(*product.Category)
+		category = &res
+	}
+	var location *model.StorageLocationResponse
+	if product.StorageLocation != nil {
+		res := toStorageLocationResponse(*product.StorageLocation)
+		location = &res
+	}
+	return model.ProductResponse{
+		ID:                   product.ID,
+		HouseholdId:          product.HouseholdId,
+		Name:                 product.Name,
+		Description:          product.Description,
+		Quantity:             product.Quantity,
+		ExpiryDate:           product.ExpiryDate,
+		Type:                 product.Type,
+		IsNotified:           product.IsNotified,
+		DaysLeft:             daysLeft,
+		Status:               status,
+		ArchivedAt:           product.ArchivedAt,
+		OpenedAt:             product.OpenedAt,
+		DaysAfterOpening:     product.DaysAfterOpening,
+		FrozenAt:             product.FrozenAt,
+		ThawedAt:             product.ThawedAt,
+		FreezerShelfLifeDays: product.FreezerShelfLifeDays,
+		EffectiveExpiryDate:  product.EffectiveExpiryDate,
+		Category:             category,
+		StorageLocation:      location,
+		CreatedAt:            product.CreatedAt,
+		UpdatedAt:            product.UpdatedAt,
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"expiry_tracker/model"
+	"testing"
+	"time"
+)
+
+func TestProductUsecase_ToProductResponse(t *testing.T) {
+	pu := &productUsecase{ep: NewExpiryPolicy(1, 3, 30, 1)}
+	expiryDate := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+	product := model.Product{
+		ID:                  1,
+		HouseholdId:         3,
+		UserId:              2,
+		Name:                "牛乳",
+		Quantity:            1,
+		ExpiryDate:          expiryDate,
+		Type:                model.ExpiryTypeUseBy,
+		EffectiveExpiryDate: expiryDate,
+	}
+
+	res := pu.toProductResponse(product, time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC), time.UTC)
+
+	if res.ID != product.ID || res.HouseholdId != product.HouseholdId {
+		t.Errorf("toProductResponse() id = %d, household_id = %d, want %d, %d", res.ID, res.HouseholdId, product.ID, product.HouseholdId)
+	}
+	if res.DaysLeft != 2 || res.Status != model.ExpiryStatusFresh {
+		t.Errorf("toProductResponse() days_left = %d, status = %s, want 2, %s", res.DaysLeft, res.Status, model.ExpiryStatusFresh)
+	}
+}
+
+func TestProductUsecase_StatusRanges(t *testing.T) {
+	pu := &productUsecase{ep: NewExpiryPolicy(1, 3, 30, 1)}
+	newYork, _ := time.LoadLocation("America/New_York")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	date := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		loc       *time.Location
+		wantToday time.Time
+	}{
+		{
+			name:      "UTC より遅れたタイムゾーン",
+			now:       time.Date(2025, 1, 11, 3, 0, 0, 0, time.UTC), // ニューヨークでは1/10 22:00
+			loc:       newYork,
+			wantToday: date(10),
+		},
+		{
+			name:      "UTC より進んだタイムゾーン",
+			now:       time.Date(2025, 1, 10, 16, 0, 0, 0, time.UTC), // 東京では1/11 01:00
+			loc:       tokyo,
+			wantToday: date(11),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, r := range pu.statusRanges(model.ExpiryStatusExpiringSoon, tt.now, tt.loc) {
+				// 期限日は UTC の 0 時で保存されているため、範囲の境界も同じ形にする
+				wantBefore := tt.wantToday.AddDate(0, 0, pu.ep.WarningDays(r.Type)+1)
+				if r.From == nil || !r.From.Equal(tt.wantToday) || r.Before == nil || !r.Before.Equal(wantBefore) {
+					t.Errorf("statusRanges() %s = [%v, %v), want [%v, %v)", r.Type, r.From, r.Before, tt.wantToday, wantBefore)
+				}
+			}
+			for _, r := range pu.statusRanges(model.ExpiryStatusExpired, tt.now, tt.loc) {
+				if r.From != nil || r.Before == nil || !r.Before.Equal(tt.wantToday) {
+					t.Errorf("statusRanges() expired %s = [%v, %v), want [nil, %v)", r.Type, r.From, r.Before, tt.wantToday)
+				}
+			}
+		})
+	}
+}
+
+func TestExpiryBounds(t *testing.T) {
+	from, before := expiryBounds("2025-01-10", "2025-01-12")
+	if from == nil || !from.Equal(time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expiryBounds() from = %v, want 2025-01-10 00:00 UTC", from)
+	}
+	if before == nil || !before.Equal(time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expiryBounds() before = %v, want 2025-01-13 00:00 UTC", before)
+	}
+
+	from, before = expiryBounds("", "")
+	if from != nil || before != nil {
+		t.Errorf("expiryBounds() = %v, %v, want open bounds", from, before)
+	}
+}
+
+func TestProductUsecase_CreateProductIgnoresState(t *testing.T) {
+	f := newNotificationFixture(0)
+	expiryDate := time.Date(2030, 1, 20, 0, 0, 0, 0, time.UTC)
+	past := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	days := 90
+	// 状態を表す項目は登録時に送られても無視する
+	product := model.Product{
+		ID:                   1,
+		UserId:               1,
+		Name:                 "鶏肉",
+		Quantity:             1,
+		ExpiryDate:           expiryDate,
+		Type:                 model.ExpiryTypeUseBy,
+		IsNotified:           true,
+		ArchivedAt:           &past,
+		OpenedAt:             &past,
+		FrozenAt:             &past,
+		ThawedAt:             &past,
+		FreezerShelfLifeDays: &days,
+		EffectiveExpiryDate:  past,
+		Category:             &model.Category{Name: "肉"},
+	}
+
+	res, err := f.pu.CreateProduct(product, 1)
+	if err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+
+	stored := f.products.products[res.ID]
+	if res.ID == 1 || stored == nil {
+		t.Fatalf("CreateProduct() id = %d, want a new product", res.ID)
+	}
+	if stored.IsNotified || stored.ArchivedAt != nil || stored.OpenedAt != nil || stored.FrozenAt != nil ||
+		stored.ThawedAt != nil || stored.FreezerShelfLifeDays != nil || stored.Category != nil {
+		t.Errorf("CreateProduct() stored %+v, want the state fields cleared", stored)
+	}
+	if !stored.EffectiveExpiryDate.Equal(expiryDate) {
+		t.Errorf("CreateProduct() effective_expiry_date = %v, want %v", stored.EffectiveExpiryDate, expiryDate)
+	}
+}
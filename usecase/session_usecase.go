@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type ISessionUsecase interface {
+	GetSessions(userId uint, sessionId uint) ([]model.SessionResponse, error)
+	RevokeSession(userId uint, sessionId uint) error
+	VerifySession(userId uint, sessionId uint) error
+}
+
+type sessionUsecase struct {
+	sr repository.ISessionRepository
+}
+
+func NewSessionUsecase(sr repository.ISessionRepository) ISessionUsecase {
+	return &sessionUsecase{sr: sr}
+}
+
+// GetSessions lists the user's active sessions, marking sessionId as the
+// current one.
+func (su *sessionUsecase) GetSessions(userId uint, sessionId uint) ([]model.SessionResponse, error) {
+	sessions := []model.Session{}
+	if err := su.sr.GetActiveSessionsByUserId(&sessions, userId, time.Now()); err != nil {
+		return nil, err
+	}
+
+	resSessions := []model.SessionResponse{}
+	for _, v := range sessions {
+		resSessions = append(resSessions, model.SessionResponse{
+			ID:         v.ID,
+			UserAgent:  v.UserAgent,
+			IPAddress:  v.IPAddress,
+			Current:    v.ID == sessionId,
+			LastUsedAt: v.LastUsedAt,
+			ExpiresAt:  v.ExpiresAt,
+			CreatedAt:  v.CreatedAt,
+		})
+	}
+	return resSessions, nil
+}
+
+func (su *sessionUsecase) RevokeSession(userId uint, sessionId uint) error {
+	if err := su.sr.RevokeSession(userId, sessionId, time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// VerifySession checks that the session an access token was issued for is
+// still active, so that logging out or revoking a session takes effect before
+// its access tokens expire.
+func (su *sessionUsecase) VerifySession(userId uint, sessionId uint) error {
+	session := model.Session{}
+	if err := su.sr.GetActiveSession(&session, sessionId, time.Now()); err != nil {
+		if apperror.KindOf(err) == apperror.KindNotFound {
+			return apperror.Unauthorized("session is no longer valid")
+		}
+		return err
+	}
+	if session.UserId != userId {
+		return apperror.Unauthorized("session is no longer valid")
+	}
+	return nil
+}
+
+// startSession records a new session for userId and issues its first tokens.
+func startSession(keys TokenKeys, sr repository.ISessionRepository, userId uint, client model.SessionClient) (model.AuthTokens, error) {
+	nonce, err := randomSecret()
+	if err != nil {
+		return model.AuthTokens{}, err
+	}
+	hash, err := hashSecret(nonce)
+	if err != nil {
+		return model.AuthTokens{}, err
+	}
+	now := time.Now()
+	session := model.Session{
+		UserId:           userId,
+		RefreshTokenHash: hash,
+		UserAgent:        client.UserAgent,
+		IPAddress:        client.IPAddress,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(model.RefreshTokenLifetime),
+	}
+	if err := sr.CreateSession(&session); err != nil {
+		return model.AuthTokens{}, err
+	}
+	return keys.sessionTokens(session, nonce)
+}
+
+// sessionTokens signs an access token and a refresh token for session. The
+// refresh token carries the nonce whose hash is stored on the session.
+func (k TokenKeys) sessionTokens(session model.Session, nonce string) (model.AuthTokens, error) {
+	accessTokenExpiresAt := time.Now().Add(model.AccessTokenLifetime)
+	accessToken, err := k.signToken(tokenPurposeLogin, jwt.MapClaims{
+		"user_id": session.UserId,
+		"sid":     session.ID,
+		"exp":     accessTokenExpiresAt.Unix(),
+	})
+	if err != nil {
+		return model.AuthTokens{}, err
+	}
+	refreshToken, err := k.signToken(tokenPurposeRefresh, jwt.MapClaims{
+		"sid":   session.ID,
+		"nonce": nonce,
+		"exp":   session.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return model.AuthTokens{}, err
+	}
+	return model.AuthTokens{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessTokenExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: session.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"expiry_tracker/validator"
+	"time"
+)
+
+type IStatsUsecase interface {
+	GetExpiredStats(userId uint, householdId uint, query model.StatsQuery) (model.ExpiredStatsResponse, error)
+	GetWasteStats(userId uint, householdId uint) (model.WasteStatsResponse, error)
+	GetConsumptionTimeStats(userId uint, householdId uint) (model.ConsumptionTimeStats, error)
+	GetUpcomingExpiryStats(userId uint, householdId uint) (model.UpcomingExpiryStats, error)
+}
+
+type statsUsecase struct {
+	sr repository.IStatsRepository
+	ur repository.IUserRepository
+	hr repository.IHouseholdRepository
+	sv validator.IStatsValidator
+}
+
+func NewStatsUsecase(sr repository.IStatsRepository, ur repository.IUserRepository, hr repository.IHouseholdRepository, sv validator.IStatsValidator) IStatsUsecase {
+	return &statsUsecase{sr: sr, ur: ur, hr: hr, sv: sv}
+}
+
+func (su *statsUsecase) GetExpiredStats(userId uint, householdId uint, query model.StatsQuery) (model.ExpiredStatsResponse, error) {
+	if err := su.sv.StatsQueryValidate(query); err != nil {
+		return model.ExpiredStatsResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(su.hr, userId, householdId, model.HouseholdRoleViewer)
+	if err != nil {
+		return model.ExpiredStatsResponse{}, err
+	}
+	loc, err := userLocation(su.ur, userId)
+	if err != nil {
+		return model.ExpiredStatsResponse{}, err
+	}
+
+	period := query.Period
+	if period == "" {
+		period = model.StatsPeriodWeek
+	}
+	today := calendarDate(time.Now(), loc)
+	var from time.Time
+	if query.From != "" {
+		from, _ = time.Parse("2006-01-02", query.From)
+	} else if period == model.StatsPeriodMonth {
+		from = time.Date(today.Year(), today.Month()-11, 1, 0, 0, 0, 0, time.UTC)
+	} else {
+		from = today.AddDate(0, 0, -7*12)
+	}
+
+	buckets := []model.ExpiredStatsBucket{}
+	if err := su.sr.GetExpiredUnused(&buckets, member.HouseholdId, period, from, today); err != nil {
+		return model.ExpiredStatsResponse{}, err
+	}
+
+	return model.ExpiredStatsResponse{Period: period, Buckets: buckets}, nil
+}
+
+func (su *statsUsecase) GetWasteStats(userId uint, householdId uint) (model.WasteStatsResponse, error) {
+	member, err := authorizeHousehold(su.hr, userId, householdId, model.HouseholdRoleViewer)
+	if err != nil {
+		return model.WasteStatsResponse{}, err
+	}
+	loc, err := userLocation(su.ur, userId)
+	if err != nil {
+		return model.WasteStatsResponse{}, err
+	}
+
+	today := calendarDate(time.Now(), loc)
+	byType := []model.WasteByTypeStats{}
+	if err := su.sr.GetWasteByType(&byType, member.HouseholdId, today); err != nil {
+		return model.WasteStatsResponse{}, err
+	}
+	byCategory := []model.WasteByCategoryStats{}
+	if err := su.sr.GetWasteByCategory(&byCategory, member.HouseholdId, today); err != nil {
+		return model.WasteStatsResponse{}, err
+	}
+	for i := range byCategory {
+		byCategory[i].WasteRate = wasteRate(byCategory[i].Consumed, byCategory[i].Wasted)
+	}
+
+	total := model.WasteStats{}
+	for i := range byType {
+		byType[i].WasteRate = wasteRate(byType[i].Consumed, byType[i].Wasted)
+		total.Consumed += byType[i].Consumed
+		total.Wasted += byType[i].Wasted
+	}
+	total.WasteRate = wasteRate(total.Consumed, total.Wasted)
+
+	return model.WasteStatsResponse{Total: total, ByType: byType, ByCategory: byCategory}, nil
+}
+
+func (su *statsUsecase) GetConsumptionTimeStats(userId uint, householdId uint) (model.ConsumptionTimeStats, error) {
+	member, err := authorizeHousehold(su.hr, userId, householdId, model.HouseholdRoleViewer)
+	if err != nil {
+		return model.ConsumptionTimeStats{}, err
+	}
+
+	stats := model.ConsumptionTimeStats{}
+	if err := su.sr.GetConsumptionTime(&stats, member.HouseholdId); err != nil {
+		return model.ConsumptionTimeStats{}, err
+	}
+	return stats, nil
+}
+
+// GetUpcomingExpiryStats counts products whose expiry is today or within the
+// next 7/30 calendar days in the user's time zone, plus those already expired.
+func (su *statsUsecase) GetUpcomingExpiryStats(userId uint, householdId uint) (model.UpcomingExpiryStats, error) {
+	member, err := authorizeHousehold(su.hr, userId, householdId, model.HouseholdRoleViewer)
+	if err != nil {
+		return model.UpcomingExpiryStats{}, err
+	}
+	loc, err := userLocation(su.ur, userId)
+	if err != nil {
+		return model.UpcomingExpiryStats{}, err
+	}
+
+	today := calendarDate(time.Now(), loc)
+	stats := model.UpcomingExpiryStats{}
+	if err := su.sr.CountExpiring(&stats.Expired, member.HouseholdId, nil, today); err != nil {
+		return model.UpcomingExpiryStats{}, err
+	}
+	if err := su.sr.CountExpiring(&stats.Within7Days, member.HouseholdId, &today, today.AddDate(0, 0, 8)); err != nil {
+		return model.UpcomingExpiryStats{}, err
+	}
+	if err := su.sr.CountExpiring(&stats.Within30Days, member.HouseholdId, &today, today.AddDate(0, 0, 31)); err != nil {
+		return model.UpcomingExpiryStats{}, err
+	}
+	return stats, nil
+}
+
+func wasteRate(consumed int64, wasted int64) float64 {
+	if consumed+wasted == 0 {
+		return 0
+	}
+	return float64(wasted) / float64(consumed+wasted)
+}
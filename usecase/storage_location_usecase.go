@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"expiry_tracker/validator"
+)
+
+type IStorageLocationUsecase interface {
+	GetAllStorageLocations(userId uint, householdId uint) ([]model.StorageLocationResponse, error)
+	CreateStorageLocation(location model.StorageLocation, householdId uint) (model.StorageLocationResponse, error)
+	UpdateStorageLocation(location model.StorageLocation, userId uint, householdId uint, locationId uint) (model.StorageLocationResponse, error)
+	DeleteStorageLocation(userId uint, householdId uint, locationId uint) error
+}
+
+type storageLocationUsecase struct {
+	lr repository.IStorageLocationRepository
+	hr repository.IHouseholdRepository
+	lv validator.IStorageLocationValidator
+}
+
+func NewStorageLocationUsecase(lr repository.IStorageLocationRepository, hr repository.IHouseholdRepository, lv validator.IStorageLocationValidator) IStorageLocationUsecase {
+	return &storageLocationUsecase{lr: lr, hr: hr, lv: lv}
+}
+
+func (lu *storageLocationUsecase) GetAllStorageLocations(userId uint, householdId uint) ([]model.StorageLocationResponse, error) {
+	member, err := authorizeHousehold(lu.hr, userId, householdId, model.HouseholdRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := []model.StorageLocation{}
+	if err := lu.lr.GetAllStorageLocations(&locations, member.HouseholdId); err != nil {
+		return nil, err
+	}
+
+	resLocations := []model.StorageLocationResponse{}
+	for _, v := range locations {
+		resLocations = append(resLocations, toStorageLocationResponse(v))
+	}
+	return resLocations, nil
+}
+
+func (lu *storageLocationUsecase) CreateStorageLocation(location model.StorageLocation, householdId uint) (model.StorageLocationResponse, error) {
+	if err := lu.lv.StorageLocationValidate(location); err != nil {
+		return model.StorageLocationResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(lu.hr, location.UserId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
+		return model.StorageLocationResponse{}, err
+	}
+	location.HouseholdId = member.HouseholdId
+	if err := lu.lr.CreateStorageLocation(&location); err != nil {
+		return model.StorageLocationResponse{}, err
+	}
+	return toStorageLocationResponse(location), nil
+}
+
+func (lu *storageLocationUsecase) UpdateStorageLocation(location model.StorageLocation, userId uint, householdId uint, locationId uint) (model.StorageLocationResponse, error) {
+	if err := lu.lv.StorageLocationValidate(location); err != nil {
+		return model.StorageLocationResponse{}, apperror.Validation(err)
+	}
+	member, err := authorizeHousehold(lu.hr, userId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
+		return model.StorageLocationResponse{}, err
+	}
+	if err := lu.lr.UpdateStorageLocation(&location, member.HouseholdId, locationId); err != nil {
+		return model.StorageLocationResponse{}, err
+	}
+	return toStorageLocationResponse(location), nil
+}
+
+func (lu *storageLocationUsecase) DeleteStorageLocation(userId uint, householdId uint, locationId uint) error {
+	member, err := authorizeHousehold(lu.hr, userId, householdId, model.HouseholdRoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := lu.lr.DeleteStorageLocation(member.HouseholdId, locationId); err != nil {
+		return err
+	}
+	return nil
+}
+
+func toStorageLocationResponse(location model.StorageLocation) model.StorageLocationResponse {
+	return model.StorageLocationResponse{
+		ID:        location.ID,
+		Name:      location.Name,
+		Kind:      location.Kind,
+		CreatedAt: location.CreatedAt,
+		UpdatedAt: location.UpdatedAt,
+	}
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"expiry_tracker/apperror"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Token purposes. Each purpose is signed with its own key, so a token issued
+// for one use is never accepted for another; in particular none of them can
+// be used as a login token.
+const (
+	tokenPurposeLogin               = ""
+	tokenPurposeRefresh             = "refresh"
+	tokenPurposeHouseholdInvitation = "household_invitation"
+	tokenPurposePasswordReset       = "password_reset"
+	tokenPurposeEmailVerification   = "email_verification"
+	tokenPurposeTwoFactorChallenge  = "two_factor_challenge"
+	tokenPurposeOidcState           = "oidc_state"
+)
+
+// secretPurposeTotp is the key purpose for encrypting TOTP secrets at rest.
+const secretPurposeTotp = "totp_secret"
+
+// TokenKeys derives the signing and encryption keys for each purpose from the
+// application secret.
+type TokenKeys struct {
+	secret []byte
+}
+
+func NewTokenKeys(secret string) TokenKeys {
+	return TokenKeys{secret: []byte(secret)}
+}
+
+func (k TokenKeys) key(purpose string) []byte {
+	if purpose == tokenPurposeLogin {
+		return k.secret
+	}
+	mac := hmac.New(sha256.New, k.secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+func (k TokenKeys) signToken(purpose string, claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(k.key(purpose))
+}
+
+// parseToken verifies a token issued by signToken for purpose, including its
+// expiry, and returns its claims.
+func (k TokenKeys) parseToken(purpose string, tokenString string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return k.key(purpose), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, apperror.Invalid("invalid or expired token")
+	}
+	return claims, nil
+}
+
+// uintClaim reads a numeric claim, which encoding/json decodes as float64.
+func uintClaim(claims jwt.MapClaims, name string) (uint, error) {
+	v, ok := claims[name].(float64)
+	if !ok || v <= 0 {
+		return 0, apperror.Invalid("invalid or expired token")
+	}
+	return uint(v), nil
+}
+
+func hashSecret(secret string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), 10)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func compareSecret(hash string, secret string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(secret)) == nil
+}
+
+// sealSecret encrypts plaintext with AES-GCM under the key for purpose, for
+// secrets that have to be stored in a recoverable form.
+func (k TokenKeys) sealSecret(purpose string, plaintext string) (string, error) {
+	gcm, err := k.secretCipher(purpose)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (k TokenKeys) openSecret(purpose string, sealed string) (string, error) {
+	gcm, err := k.secretCipher(purpose)
+	if err != nil {
+		return "", err
+	}
+	b, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(b) < gcm.NonceSize() {
+		return "", errors.New("sealed secret is too short")
+	}
+	plaintext, err := gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (k TokenKeys) secretCipher(purpose string) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k.key(purpose))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// randomSecret returns a URL-safe random string with 256 bits of entropy.
+func randomSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"expiry_tracker/model"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestToken_SignAndParse(t *testing.T) {
+	keys := NewTokenKeys("test-secret")
+	claims := jwt.MapClaims{"invitation_id": 42, "exp": time.Now().Add(time.Hour).Unix()}
+
+	token, err := keys.signToken(tokenPurposeHouseholdInvitation, claims)
+	if err != nil {
+		t.Fatalf("signToken() error = %v", err)
+	}
+
+	parsed, err := keys.parseToken(tokenPurposeHouseholdInvitation, token)
+	if err != nil {
+		t.Fatalf("parseToken() error = %v", err)
+	}
+	if id, err := uintClaim(parsed, "invitation_id"); err != nil || id != 42 {
+		t.Errorf("uintClaim() = %v, %v, want 42", id, err)
+	}
+
+	if _, err := keys.parseToken(tokenPurposeLogin, token); err == nil {
+		t.Error("parseToken() accepted a token issued for another purpose")
+	}
+	if _, err := NewTokenKeys("other-secret").parseToken(tokenPurposeHouseholdInvitation, token); err == nil {
+		t.Error("parseToken() accepted a token signed with another secret")
+	}
+}
+
+func TestToken_Expired(t *testing.T) {
+	keys := NewTokenKeys("test-secret")
+	token, err := keys.signToken(tokenPurposeHouseholdInvitation, jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("signToken() error = %v", err)
+	}
+	if _, err := keys.parseToken(tokenPurposeHouseholdInvitation, token); err == nil {
+		t.Error("parseToken() accepted an expired token")
+	}
+}
+
+func TestToken_HashSecret(t *testing.T) {
+	secret, err := randomSecret()
+	if err != nil {
+		t.Fatalf("randomSecret() error = %v", err)
+	}
+	hash, err := hashSecret(secret)
+	if err != nil {
+		t.Fatalf("hashSecret() error = %v", err)
+	}
+	if !compareSecret(hash, secret) {
+		t.Error("compareSecret() = false for the original secret")
+	}
+	if compareSecret(hash, secret+"x") {
+		t.Error("compareSecret() = true for a different secret")
+	}
+}
+
+func TestToken_SessionTokens(t *testing.T) {
+	keys := NewTokenKeys("test-secret")
+	session := model.Session{ID: 7, UserId: 3, ExpiresAt: time.Now().Add(model.RefreshTokenLifetime)}
+
+	tokens, err := keys.sessionTokens(session, "nonce")
+	if err != nil {
+		t.Fatalf("sessionTokens() error = %v", err)
+	}
+
+	access, err := keys.parseToken(tokenPurposeLogin, tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("parseToken(access) error = %v", err)
+	}
+	if id, err := uintClaim(access, "user_id"); err != nil || id != 3 {
+		t.Errorf("access user_id = %v, %v, want 3", id, err)
+	}
+	if id, err := uintClaim(access, "sid"); err != nil || id != 7 {
+		t.Errorf("access sid = %v, %v, want 7", id, err)
+	}
+	if !tokens.AccessTokenExpiresAt.Before(tokens.RefreshTokenExpiresAt) {
+		t.Error("access token should expire before the refresh token")
+	}
+
+	refresh, err := keys.parseToken(tokenPurposeRefresh, tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("parseToken(refresh) error = %v", err)
+	}
+	if nonce, _ := refresh["nonce"].(string); nonce != "nonce" {
+		t.Errorf("refresh nonce = %q, want %q", nonce, "nonce")
+	}
+	if _, err := keys.parseToken(tokenPurposeLogin, tokens.RefreshToken); err == nil {
+		t.Error("refresh token was accepted as an access token")
+	}
+}
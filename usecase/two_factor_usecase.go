@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"expiry_tracker/apperror"
+	"expiry_tracker/model"
+	"expiry_tracker/repository"
+	"expiry_tracker/totp"
+	"expiry_tracker/validator"
+	"fmt"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type ITwoFactorUsecase interface {
+	Enroll(userId uint) (model.TwoFactorEnrollResponse, error)
+	Confirm(request model.TwoFactorCodeRequest, userId uint) (model.RecoveryCodesResponse, error)
+	Disable(request model.TwoFactorDisableRequest, userId uint) error
+	Login(request model.TwoFactorLoginRequest, client model.SessionClient) (model.AuthTokens, error)
+}
+
+type twoFactorUsecase struct {
+	ur     repository.IUserRepository
+	tr     repository.ITwoFactorRepository
+	sr     repository.ISessionRepository
+	ll     IAttemptLimiter
+	tv     validator.ITwoFactorValidator
+	issuer string
+	keys   TokenKeys
+}
+
+func NewTwoFactorUsecase(
+	ur repository.IUserRepository,
+	tr repository.ITwoFactorRepository,
+	sr repository.ISessionRepository,
+	ll IAttemptLimiter,
+	tv validator.ITwoFactorValidator,
+	issuer string,
+	keys TokenKeys,
+) ITwoFactorUsecase {
+	return &twoFactorUsecase{ur: ur, tr: tr, sr: sr, ll: ll, tv: tv, issuer: issuer, keys: keys}
+}
+
+// Enroll starts enrollment with a new secret. Two-factor authentication is
+// not enabled until Confirm receives a code generated from it.
+func (tu *twoFactorUsecase) Enroll(userId uint) (model.TwoFactorEnrollResponse, error) {
+	user := model.User{}
+	if err := tu.ur.GetUserById(&user, userId); err != nil {
+		return model.TwoFactorEnrollResponse{}, err
+	}
+	if user.TotpEnabledAt != nil {
+		return model.TwoFactorEnrollResponse{}, apperror.Conflict("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return model.TwoFactorEnrollResponse{}, err
+	}
+	sealed, err := tu.keys.sealSecret(secretPurposeTotp, secret)
+	if err != nil {
+		return model.TwoFactorEnrollResponse{}, err
+	}
+	if err := tu.tr.SetTotpSecret(userId, sealed); err != nil {
+		return model.TwoFactorEnrollResponse{}, err
+	}
+
+	return model.TwoFactorEnrollResponse{
+		Secret:     secret,
+		OtpauthURI: totp.URI(tu.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm enables two-factor authentication once the user proves their
+// authenticator works, and returns a new set of recovery codes.
+func (tu *twoFactorUsecase) Confirm(request model.TwoFactorCodeRequest, userId uint) (model.RecoveryCodesResponse, error) {
+	if err := tu.tv.TwoFactorCodeValidate(request); err != nil {
+		return model.RecoveryCodesResponse{}, apperror.Validation(err)
+	}
+	user := model.User{}
+	if err := tu.ur.GetUserById(&user, userId); err != nil {
+		return model.RecoveryCodesResponse{}, err
+	}
+	if user.TotpEnabledAt != nil {
+		return model.RecoveryCodesResponse{}, apperror.Conflict("two-factor authentication is already enabled")
+	}
+	if user.TotpSecret == "" {
+		return model.RecoveryCodesResponse{}, apperror.Conflict("two-factor enrollment has not been started")
+	}
+	secret, err := tu.keys.openSecret(secretPurposeTotp, user.TotpSecret)
+	if err != nil {
+		return model.RecoveryCodesResponse{}, err
+	}
+	step, ok := totp.Validate(secret, request.Code, time.Now())
+	if !ok {
+		return model.RecoveryCodesResponse{}, invalidCodeError()
+	}
+
+	codes := []string{}
+	hashes := []string{}
+	for i := 0; i < model.RecoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return model.RecoveryCodesResponse{}, err
+		}
+		hash, err := hashSecret(normalizeRecoveryCode(code))
+		if err != nil {
+			return model.RecoveryCodesResponse{}, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hash)
+	}
+	if err := tu.tr.EnableTotp(userId, step, hashes, time.Now()); err != nil {
+		return model.RecoveryCodesResponse{}, err
+	}
+	return model.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns two-factor authentication off. It requires both the current
+// password and a code, so that neither a stolen session nor a stolen
+// password alone is enough.
+func (tu *twoFactorUsecase) Disable(request model.TwoFactorDisableRequest, userId uint) error {
+	if err := tu.tv.TwoFactorDisableValidate(request); err != nil {
+		return apperror.Validation(err)
+	}
+	user := model.User{}
+	if err := tu.ur.GetUserById(&user, userId); err != nil {
+		return err
+	}
+	if user.TotpEnabledAt == nil {
+		return apperror.Conflict("two-factor authentication is not enabled")
+	}
+	if err := checkCurrentPassword(user, request.CurrentPassword); err != nil {
+		return err
+	}
+	ok, err := tu.verifyCode(user, request.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return invalidCodeError()
+	}
+	if err := tu.tr.DisableTotp(userId); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Login completes a login started by userUsecase.Login: it exchanges the
+// challenge token and a valid code for a new session. Failed codes count
+// towards the same backoff as failed passwords.
+func (tu *twoFactorUsecase) Login(request model.TwoFactorLoginRequest, client model.SessionClient) (model.AuthTokens, error) {
+	if err := tu.tv.TwoFactorLoginValidate(request); err != nil {
+		return model.AuthTokens{}, apperror.Validation(err)
+	}
+	claims, err := tu.keys.parseToken(tokenPurposeTwoFactorChallenge, request.ChallengeToken)
+	if err != nil {
+		return model.AuthTokens{}, apperror.Unauthorized("invalid or expired challenge token")
+	}
+	userId, err := uintClaim(claims, "user_id")
+	if err != nil {
+		return model.AuthTokens{}, apperror.Unauthorized("invalid or expired challenge token")
+	}
+	key := fmt.Sprintf("login:2fa:%d", userId)
+	if err := tu.ll.Check(key); err != nil {
+		return model.AuthTokens{}, err
+	}
+
+	user := model.User{}
+	if err := tu.ur.GetUserById(&user, userId); err != nil {
+		if apperror.KindOf(err) == apperror.KindNotFound {
+			return model.AuthTokens{}, apperror.Unauthorized("invalid or expired challenge token")
+		}
+		return model.AuthTokens{}, err
+	}
+	if user.TotpEnabledAt == nil {
+		return model.AuthTokens{}, apperror.Unauthorized("invalid or expired challenge token")
+	}
+	ok, err := tu.verifyCode(user, request.Code)
+	if err != nil {
+		return model.AuthTokens{}, err
+	}
+	if !ok {
+		if err := tu.ll.Record(key); err != nil {
+			return model.AuthTokens{}, err
+		}
+		return model.AuthTokens{}, apperror.Unauthorized("invalid two-factor code")
+	}
+	if err := tu.ll.Reset(key); err != nil {
+		return model.AuthTokens{}, err
+	}
+
+	return startSession(tu.keys, tu.sr, user.ID, client)
+}
+
+// verifyCode accepts either a code from the authenticator app or an unused
+// recovery code. Each code is accepted only once.
+func (tu *twoFactorUsecase) verifyCode(user model.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits && strings.Trim(code, "0123456789") == "" {
+		secret, err := tu.keys.openSecret(secretPurposeTotp, user.TotpSecret)
+		if err != nil {
+			return false, err
+		}
+		step, ok := totp.Validate(secret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		return tu.tr.UseTotpStep(user.ID, step)
+	}
+
+	recoveryCodes := []model.RecoveryCode{}
+	if err := tu.tr.GetUnusedRecoveryCodes(&recoveryCodes, user.ID); err != nil {
+		return false, err
+	}
+	normalized := normalizeRecoveryCode(code)
+	for _, v := range recoveryCodes {
+		if compareSecret(v.CodeHash, normalized) {
+			return tu.tr.UseRecoveryCode(v.ID, time.Now())
+		}
+	}
+	return false, nil
+}
+
+func invalidCodeError() error {
+	return apperror.Validation(validation.Errors{
+		"code": validation.NewError("validation_invalid_code", "code is invalid"),
+	})
+}
+
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijkmnpqrstuvwxyz23456789").WithPadding(base32.NoPadding)
+
+// newRecoveryCode returns a 50-bit code formatted as "xxxxx-xxxxx", avoiding
+// characters that are easily confused when typed.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	s := recoveryCodeEncoding.EncodeToString(b)[:10]
+	return s[:5] + "-" + s[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -1,82 +1,342 @@
 package usecase
 
 import (
-	"errors"
+	"expiry_tracker/apperror"
+	"expiry_tracker/mailer"
 	"expiry_tracker/model"
 	"expiry_tracker/repository"
 	"expiry_tracker/validator"
-	"os"
+	"log"
+	"strings"
+	"sync"
 	"time"
 
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type IUserUsecase interface {
 	SignUp(user *model.User) (model.UserResponse, error)
-	Login(user *model.User) (string, error)
+	Login(user *model.User, client model.SessionClient) (model.LoginResult, error)
+	RefreshToken(refreshToken string, client model.SessionClient) (model.AuthTokens, error)
+	LogOut(refreshToken string) error
+	GetMe(userId uint) (model.UserResponse, error)
+	UpdateProfile(request model.UpdateProfileRequest, userId uint) (model.UserResponse, error)
+	ChangeEmail(request model.ChangeEmailRequest, userId uint) (model.UserResponse, error)
+	ChangePassword(request model.ChangePasswordRequest, userId uint, sessionId uint) error
+	DeleteAccount(request model.DeleteAccountRequest, userId uint) error
 }
 
 type userUsecase struct {
-	ur repository.IUserRepository
-	uv validator.IUserValidator
+	ur    repository.IUserRepository
+	sr    repository.ISessionRepository
+	er    repository.IEmailVerificationRepository
+	m     mailer.IMailer
+	ll    IAttemptLimiter
+	uv    validator.IUserValidator
+	feURL string
+	keys  TokenKeys
 }
 
-func NewUserUsecase(ur repository.IUserRepository, uv validator.IUserValidator) IUserUsecase {
-	return &userUsecase{ur: ur, uv: uv}
+func NewUserUsecase(
+	ur repository.IUserRepository,
+	sr repository.ISessionRepository,
+	er repository.IEmailVerificationRepository,
+	m mailer.IMailer,
+	ll IAttemptLimiter,
+	uv validator.IUserValidator,
+	feURL string,
+	keys TokenKeys,
+) IUserUsecase {
+	return &userUsecase{ur: ur, sr: sr, er: er, m: m, ll: ll, uv: uv, feURL: feURL, keys: keys}
 }
 
+// dummyPasswordHash is compared against when logging in with an unknown email
+// address.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := hashSecret("dummy password")
+	return hash
+})
+
 func (uu *userUsecase) SignUp(user *model.User) (model.UserResponse, error) {
 	if err := uu.uv.SignUpUserValidate(*user); err != nil {
-		return model.UserResponse{}, err
+		return model.UserResponse{}, apperror.Validation(err)
 	}
-	hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), 10)
+	hash, err := hashSecret(user.Password)
 	if err != nil {
 		return model.UserResponse{}, err
 	}
 
+	timeZone := user.TimeZone
+	if timeZone == "" {
+		timeZone = model.DefaultTimeZone
+	}
+
 	newUser := model.User{
 		Email:    user.Email,
-		Password: string(hash),
+		Password: hash,
 		Name:     user.Name,
+		TimeZone: timeZone,
 	}
 
-	if err := uu.ur.CreateUser(&newUser); err != nil {
+	household := model.Household{Name: model.DefaultHouseholdName}
+	if err := uu.ur.CreateUser(&newUser, &household); err != nil {
 		return model.UserResponse{}, err
 	}
-
-	resUser := model.UserResponse{
-		ID:    newUser.ID,
-		Email: newUser.Email,
-		Name:  newUser.Name,
+	// 確認メールが送れなくてもアカウントは作成済みのため、再送で回復できるようにする
+	if err := sendEmailVerification(uu.keys, uu.feURL, uu.er, uu.m, newUser); err != nil {
+		log.Printf("failed to send verification email to user %d: %v", newUser.ID, err)
 	}
 
-	return resUser, nil
+	return toUserResponse(newUser), nil
 }
 
-func (uu *userUsecase) Login(user *model.User) (string, error) {
+func (uu *userUsecase) Login(user *model.User, client model.SessionClient) (model.LoginResult, error) {
 	if err := uu.uv.LoginUserValidate(*user); err != nil {
-		return "", err
+		return model.LoginResult{}, apperror.Validation(err)
 	}
+	keys := []string{
+		"login:account:" + strings.ToLower(user.Email),
+		"login:ip:" + client.IPAddress,
+	}
+	for _, key := range keys {
+		if err := uu.ll.Check(key); err != nil {
+			return model.LoginResult{}, err
+		}
+	}
+
 	storedUser := model.User{}
 	if err := uu.ur.GetUserByEmail(&storedUser, user.Email); err != nil {
-		return "", errors.New("user not found")
+		if apperror.KindOf(err) != apperror.KindNotFound {
+			return model.LoginResult{}, err
+		}
+		// 未登録のアドレスでも同じ時間をかけ、応答時間から登録の有無を推測させない
+		storedUser.Password = dummyPasswordHash()
+	}
+
+	if storedUser.ID == 0 || !compareSecret(storedUser.Password, user.Password) {
+		for _, key := range keys {
+			if err := uu.ll.Record(key); err != nil {
+				return model.LoginResult{}, err
+			}
+		}
+		return model.LoginResult{}, apperror.Unauthorized("invalid email or password")
+	}
+	if err := uu.ll.Reset(keys[0]); err != nil {
+		return model.LoginResult{}, err
+	}
+
+	return completeLogin(uu.keys, uu.sr, storedUser, client)
+}
+
+// completeLogin finishes a login once the user has been authenticated by a
+// password or an identity provider: it starts a session, or returns a
+// challenge token when two-factor authentication is enabled.
+func completeLogin(keys TokenKeys, sr repository.ISessionRepository, user model.User, client model.SessionClient) (model.LoginResult, error) {
+	if user.TotpEnabledAt != nil {
+		// 二要素認証が有効な場合は、コードの確認が済むまでセッションを作らない
+		challengeToken, err := keys.signToken(tokenPurposeTwoFactorChallenge, jwt.MapClaims{
+			"user_id": user.ID,
+			"exp":     time.Now().Add(model.TwoFactorChallengeLifetime).Unix(),
+		})
+		if err != nil {
+			return model.LoginResult{}, err
+		}
+		return model.LoginResult{ChallengeToken: challengeToken}, nil
+	}
+
+	tokens, err := startSession(keys, sr, user.ID, client)
+	if err != nil {
+		return model.LoginResult{}, err
+	}
+	return model.LoginResult{Tokens: tokens}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new pair of tokens. Refresh
+// tokens are single use: each refresh rotates the nonce stored on the
+// session, and presenting an already rotated token revokes the session, since
+// it means the token has been copied.
+func (uu *userUsecase) RefreshToken(refreshToken string, client model.SessionClient) (model.AuthTokens, error) {
+	session, nonce, err := uu.sessionFor(refreshToken)
+	if err != nil {
+		return model.AuthTokens{}, err
+	}
+	if !compareSecret(session.RefreshTokenHash, nonce) {
+		if err := uu.sr.RevokeSession(session.UserId, session.ID, time.Now()); err != nil && apperror.KindOf(err) != apperror.KindNotFound {
+			return model.AuthTokens{}, err
+		}
+		return model.AuthTokens{}, apperror.Unauthorized("refresh token has already been used")
+	}
+
+	newNonce, err := randomSecret()
+	if err != nil {
+		return model.AuthTokens{}, err
+	}
+	hash, err := hashSecret(newNonce)
+	if err != nil {
+		return model.AuthTokens{}, err
+	}
+	session.UserAgent = client.UserAgent
+	session.IPAddress = client.IPAddress
+	if err := uu.sr.RotateSession(&session, hash, time.Now()); err != nil {
+		return model.AuthTokens{}, err
+	}
+	return uu.keys.sessionTokens(session, newNonce)
+}
+
+// LogOut revokes the session of refreshToken. Logging out always succeeds, so
+// a missing, invalid or already revoked token is ignored.
+func (uu *userUsecase) LogOut(refreshToken string) error {
+	if refreshToken == "" {
+		return nil
+	}
+	session, _, err := uu.sessionFor(refreshToken)
+	if err != nil {
+		if apperror.KindOf(err) == apperror.KindUnauthorized {
+			return nil
+		}
+		return err
+	}
+	if err := uu.sr.RevokeSession(session.UserId, session.ID, time.Now()); err != nil && apperror.KindOf(err) != apperror.KindNotFound {
+		return err
+	}
+	return nil
+}
+
+func (uu *userUsecase) GetMe(userId uint) (model.UserResponse, error) {
+	user := model.User{}
+	if err := uu.ur.GetUserById(&user, userId); err != nil {
+		return model.UserResponse{}, err
 	}
+	return toUserResponse(user), nil
+}
+
+func (uu *userUsecase) UpdateProfile(request model.UpdateProfileRequest, userId uint) (model.UserResponse, error) {
+	if err := uu.uv.UpdateProfileValidate(request); err != nil {
+		return model.UserResponse{}, apperror.Validation(err)
+	}
+	timeZone := request.TimeZone
+	if timeZone == "" {
+		timeZone = model.DefaultTimeZone
+	}
+	if err := uu.ur.UpdateProfile(userId, request.Name, timeZone); err != nil {
+		return model.UserResponse{}, err
+	}
+	return uu.GetMe(userId)
+}
 
-	err := bcrypt.CompareHashAndPassword([]byte(storedUser.Password), []byte(user.Password))
+// ChangeEmail changes the email address after confirming the current
+// password. The new address is unverified until the user follows the
+// verification email sent to it.
+func (uu *userUsecase) ChangeEmail(request model.ChangeEmailRequest, userId uint) (model.UserResponse, error) {
+	if err := uu.uv.ChangeEmailValidate(request); err != nil {
+		return model.UserResponse{}, apperror.Validation(err)
+	}
+	user, err := uu.userWithPassword(userId, request.CurrentPassword)
 	if err != nil {
-		return "", errors.New("invalid password")
+		return model.UserResponse{}, err
+	}
+	if user.Email == request.Email {
+		return toUserResponse(user), nil
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": storedUser.ID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
-	})
+	if err := uu.ur.UpdateEmail(userId, request.Email); err != nil {
+		return model.UserResponse{}, err
+	}
+	user.Email = request.Email
+	user.EmailVerifiedAt = nil
+	if err := sendEmailVerification(uu.keys, uu.feURL, uu.er, uu.m, user); err != nil {
+		log.Printf("failed to send verification email to user %d: %v", user.ID, err)
+	}
+	return toUserResponse(user), nil
+}
 
-	tokenString, err := token.SignedString([]byte(os.Getenv("SECRET")))
+// ChangePassword sets a new password after confirming the current one. Other
+// sessions are logged out; sessionId, the one making the change, is kept.
+func (uu *userUsecase) ChangePassword(request model.ChangePasswordRequest, userId uint, sessionId uint) error {
+	if err := uu.uv.ChangePasswordValidate(request); err != nil {
+		return apperror.Validation(err)
+	}
+	if _, err := uu.userWithPassword(userId, request.CurrentPassword); err != nil {
+		return err
+	}
+	hash, err := hashSecret(request.NewPassword)
 	if err != nil {
-		return "", err
+		return err
+	}
+	if err := uu.ur.UpdatePassword(userId, hash, sessionId, time.Now()); err != nil {
+		return err
 	}
+	return nil
+}
 
-	return tokenString, nil
+// DeleteAccount soft deletes the account after confirming the password,
+// together with the households only the user belongs to.
+func (uu *userUsecase) DeleteAccount(request model.DeleteAccountRequest, userId uint) error {
+	if err := uu.uv.DeleteAccountValidate(request); err != nil {
+		return apperror.Validation(err)
+	}
+	if _, err := uu.userWithPassword(userId, request.CurrentPassword); err != nil {
+		return err
+	}
+	if err := uu.ur.DeleteUser(userId, time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// userWithPassword loads the user and checks password against theirs, for
+// changes that require re-entering the current password.
+func (uu *userUsecase) userWithPassword(userId uint, password string) (model.User, error) {
+	user := model.User{}
+	if err := uu.ur.GetUserById(&user, userId); err != nil {
+		return model.User{}, err
+	}
+	if err := checkCurrentPassword(user, password); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+func checkCurrentPassword(user model.User, password string) error {
+	if !compareSecret(user.Password, password) {
+		return apperror.Validation(validation.Errors{
+			"current_password": validation.NewError("validation_current_password", "current password is incorrect"),
+		})
+	}
+	return nil
+}
+
+// sessionFor verifies refreshToken and returns its active session together
+// with the nonce it carries.
+func (uu *userUsecase) sessionFor(refreshToken string) (model.Session, string, error) {
+	claims, err := uu.keys.parseToken(tokenPurposeRefresh, refreshToken)
+	if err != nil {
+		return model.Session{}, "", apperror.Unauthorized("invalid or expired refresh token")
+	}
+	sessionId, err := uintClaim(claims, "sid")
+	if err != nil {
+		return model.Session{}, "", apperror.Unauthorized("invalid or expired refresh token")
+	}
+	nonce, _ := claims["nonce"].(string)
+
+	session := model.Session{}
+	if err := uu.sr.GetActiveSession(&session, sessionId, time.Now()); err != nil {
+		if apperror.KindOf(err) == apperror.KindNotFound {
+			return model.Session{}, "", apperror.Unauthorized("session is no longer valid")
+		}
+		return model.Session{}, "", err
+	}
+	return session, nonce, nil
+}
+
+func toUserResponse(user model.User) model.UserResponse {
+	return model.UserResponse{
+		ID:               user.ID,
+		Email:            user.Email,
+		Name:             user.Name,
+		TimeZone:         user.TimeZone,
+		EmailVerifiedAt:  user.EmailVerifiedAt,
+		TwoFactorEnabled: user.TotpEnabledAt != nil,
+	}
 }
@@ -0,0 +1,40 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type IApiTokenValidator interface {
+	ApiTokenValidate(request model.ApiTokenRequest) error
+}
+
+type apiTokenValidator struct{}
+
+func NewApiTokenValidator() IApiTokenValidator {
+	return &apiTokenValidator{}
+}
+
+func (av *apiTokenValidator) ApiTokenValidate(request model.ApiTokenRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.Name,
+			validation.Required.Error("name is required"),
+			validation.RuneLength(1, 50).Error("limited max 50 char"),
+		),
+		validation.Field(
+			&request.Scope,
+			validation.Required.Error("scope is required"),
+			validation.In(
+				model.ApiTokenScopeRead,
+				model.ApiTokenScopeWrite,
+			).Error("invalid scope"),
+		),
+		validation.Field(
+			&request.ExpiresInDays,
+			validation.Min(1).Error("expires_in_days must be greater than 0"),
+			validation.Max(model.MaxApiTokenExpiresIn).Error("expires_in_days must be 365 or less"),
+		),
+	)
+}
@@ -0,0 +1,66 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+	"testing"
+)
+
+func TestApiTokenValidator_ApiTokenValidate(t *testing.T) {
+	validator := NewApiTokenValidator()
+
+	tests := []struct {
+		name    string
+		request model.ApiTokenRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "有効なリクエスト",
+			request: model.ApiTokenRequest{Name: "Home Assistant", Scope: model.ApiTokenScopeRead, ExpiresInDays: 90},
+			wantErr: false,
+		},
+		{
+			name:    "有効期限を省略",
+			request: model.ApiTokenRequest{Name: "Raspberry Pi", Scope: model.ApiTokenScopeWrite},
+			wantErr: false,
+		},
+		{
+			name:    "名前が空",
+			request: model.ApiTokenRequest{Scope: model.ApiTokenScopeRead},
+			wantErr: true,
+			errMsg:  "name: name is required.",
+		},
+		{
+			name:    "無効なスコープ",
+			request: model.ApiTokenRequest{Name: "script", Scope: "admin"},
+			wantErr: true,
+			errMsg:  "scope: invalid scope.",
+		},
+		{
+			name:    "有効期限が長すぎる",
+			request: model.ApiTokenRequest{Name: "script", Scope: model.ApiTokenScopeRead, ExpiresInDays: 366},
+			wantErr: true,
+			errMsg:  "expires_in_days: expires_in_days must be 365 or less.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ApiTokenValidate(tt.request)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ApiTokenValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("ApiTokenValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("ApiTokenValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
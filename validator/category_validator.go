@@ -0,0 +1,32 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type ICategoryValidator interface {
+	CategoryValidate(category model.Category) error
+}
+
+type categoryValidator struct{}
+
+func NewCategoryValidator() ICategoryValidator {
+	return &categoryValidator{}
+}
+
+func (cv *categoryValidator) CategoryValidate(category model.Category) error {
+	return validation.ValidateStruct(&category,
+		validation.Field(
+			&category.Name,
+			validation.Required.Error("name is required"),
+			validation.RuneLength(1, 30).Error("limited max 30 char"),
+		),
+		validation.Field(
+			&category.FreezerShelfLifeDays,
+			validation.NilOrNotEmpty.Error("freezer_shelf_life_days must be greater than 0"),
+			validation.Min(1).Error("freezer_shelf_life_days must be greater than 0"),
+		),
+	)
+}
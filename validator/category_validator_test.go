@@ -0,0 +1,66 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+	"testing"
+)
+
+func TestCategoryValidator_CategoryValidate(t *testing.T) {
+	validator := NewCategoryValidator()
+
+	tests := []struct {
+		name     string
+		category model.Category
+		wantErr  bool
+		errMsg   string
+	}{
+		{
+			name:     "正常なカテゴリ",
+			category: model.Category{Name: "乳製品"},
+			wantErr:  false,
+		},
+		{
+			name:     "名前が空",
+			category: model.Category{Name: ""},
+			wantErr:  true,
+			errMsg:   "name: name is required.",
+		},
+		{
+			name:     "名前が長すぎる",
+			category: model.Category{Name: "1234567890123456789012345678901"}, // 31文字
+			wantErr:  true,
+			errMsg:   "name: limited max 30 char.",
+		},
+		{
+			name:     "冷凍保存の日数を指定",
+			category: model.Category{Name: "肉", FreezerShelfLifeDays: intPtr(30)},
+			wantErr:  false,
+		},
+		{
+			name:     "冷凍保存の日数が0",
+			category: model.Category{Name: "肉", FreezerShelfLifeDays: intPtr(0)},
+			wantErr:  true,
+			errMsg:   "freezer_shelf_life_days: freezer_shelf_life_days must be greater than 0.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.CategoryValidate(tt.category)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CategoryValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("CategoryValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("CategoryValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
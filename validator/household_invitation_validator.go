@@ -0,0 +1,52 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+type IHouseholdInvitationValidator interface {
+	HouseholdInvitationValidate(request model.HouseholdInvitationRequest) error
+	InvitationTokenValidate(request model.InvitationTokenRequest) error
+}
+
+type householdInvitationValidator struct{}
+
+func NewHouseholdInvitationValidator() IHouseholdInvitationValidator {
+	return &householdInvitationValidator{}
+}
+
+func (iv *householdInvitationValidator) HouseholdInvitationValidate(request model.HouseholdInvitationRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.Email,
+			validation.RuneLength(0, 30).Error("limited max 30 char"),
+			is.EmailFormat.Error("is not valid email format"),
+		),
+		validation.Field(
+			&request.Role,
+			validation.Required.Error("role is required"),
+			validation.In(
+				model.HouseholdRoleOwner,
+				model.HouseholdRoleEditor,
+				model.HouseholdRoleViewer,
+			).Error("invalid role"),
+		),
+		validation.Field(
+			&request.ExpiresInDays,
+			validation.Min(1).Error("expires_in_days must be greater than 0"),
+			validation.Max(model.MaxInvitationExpiresInDays).Error("expires_in_days must be 30 or less"),
+		),
+	)
+}
+
+func (iv *householdInvitationValidator) InvitationTokenValidate(request model.InvitationTokenRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.Token,
+			validation.Required.Error("token is required"),
+		),
+	)
+}
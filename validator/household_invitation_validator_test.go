@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+	"testing"
+)
+
+func TestHouseholdInvitationValidator_HouseholdInvitationValidate(t *testing.T) {
+	validator := NewHouseholdInvitationValidator()
+
+	tests := []struct {
+		name    string
+		request model.HouseholdInvitationRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "リンクによる招待",
+			request: model.HouseholdInvitationRequest{Role: model.HouseholdRoleEditor},
+			wantErr: false,
+		},
+		{
+			name:    "メールによる招待",
+			request: model.HouseholdInvitationRequest{Email: "partner@example.com", Role: model.HouseholdRoleViewer, ExpiresInDays: 3},
+			wantErr: false,
+		},
+		{
+			name:    "役割が空",
+			request: model.HouseholdInvitationRequest{},
+			wantErr: true,
+			errMsg:  "role: role is required.",
+		},
+		{
+			name:    "無効なメールアドレス",
+			request: model.HouseholdInvitationRequest{Email: "partner", Role: model.HouseholdRoleEditor},
+			wantErr: true,
+			errMsg:  "email: is not valid email format.",
+		},
+		{
+			name:    "有効期限が長すぎる",
+			request: model.HouseholdInvitationRequest{Role: model.HouseholdRoleEditor, ExpiresInDays: 31},
+			wantErr: true,
+			errMsg:  "expires_in_days: expires_in_days must be 30 or less.",
+		},
+		{
+			name:    "有効期限が負の値",
+			request: model.HouseholdInvitationRequest{Role: model.HouseholdRoleEditor, ExpiresInDays: -1},
+			wantErr: true,
+			errMsg:  "expires_in_days: expires_in_days must be greater than 0.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.HouseholdInvitationValidate(tt.request)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("HouseholdInvitationValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("HouseholdInvitationValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("HouseholdInvitationValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestHouseholdInvitationValidator_InvitationTokenValidate(t *testing.T) {
+	validator := NewHouseholdInvitationValidator()
+
+	if err := validator.InvitationTokenValidate(model.InvitationTokenRequest{Token: "token"}); err != nil {
+		t.Errorf("InvitationTokenValidate() error = %v, wantErr false", err)
+	}
+	err := validator.InvitationTokenValidate(model.InvitationTokenRequest{})
+	if err == nil || err.Error() != "token: token is required." {
+		t.Errorf("InvitationTokenValidate() error = %v, wantErrMsg %v", err, "token: token is required.")
+	}
+}
@@ -0,0 +1,42 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type IHouseholdValidator interface {
+	HouseholdValidate(household model.Household) error
+	HouseholdMemberRoleValidate(request model.HouseholdMemberRoleRequest) error
+}
+
+type householdValidator struct{}
+
+func NewHouseholdValidator() IHouseholdValidator {
+	return &householdValidator{}
+}
+
+func (hv *householdValidator) HouseholdValidate(household model.Household) error {
+	return validation.ValidateStruct(&household,
+		validation.Field(
+			&household.Name,
+			validation.Required.Error("name is required"),
+			validation.RuneLength(1, 30).Error("limited max 30 char"),
+		),
+	)
+}
+
+func (hv *householdValidator) HouseholdMemberRoleValidate(request model.HouseholdMemberRoleRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.Role,
+			validation.Required.Error("role is required"),
+			validation.In(
+				model.HouseholdRoleOwner,
+				model.HouseholdRoleEditor,
+				model.HouseholdRoleViewer,
+			).Error("invalid role"),
+		),
+	)
+}
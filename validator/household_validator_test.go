@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+	"testing"
+)
+
+func TestHouseholdValidator_HouseholdValidate(t *testing.T) {
+	validator := NewHouseholdValidator()
+
+	tests := []struct {
+		name      string
+		household model.Household
+		wantErr   bool
+		errMsg    string
+	}{
+		{
+			name:      "正常な世帯",
+			household: model.Household{Name: "実家"},
+			wantErr:   false,
+		},
+		{
+			name:      "名前が空",
+			household: model.Household{Name: ""},
+			wantErr:   true,
+			errMsg:    "name: name is required.",
+		},
+		{
+			name:      "名前が長すぎる",
+			household: model.Household{Name: "1234567890123456789012345678901"}, // 31文字
+			wantErr:   true,
+			errMsg:    "name: limited max 30 char.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.HouseholdValidate(tt.household)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("HouseholdValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("HouseholdValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("HouseholdValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestHouseholdValidator_HouseholdMemberRoleValidate(t *testing.T) {
+	validator := NewHouseholdValidator()
+
+	tests := []struct {
+		name    string
+		request model.HouseholdMemberRoleRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "編集者",
+			request: model.HouseholdMemberRoleRequest{Role: model.HouseholdRoleEditor},
+			wantErr: false,
+		},
+		{
+			name:    "役割が空",
+			request: model.HouseholdMemberRoleRequest{},
+			wantErr: true,
+			errMsg:  "role: role is required.",
+		},
+		{
+			name:    "無効な役割",
+			request: model.HouseholdMemberRoleRequest{Role: "admin"},
+			wantErr: true,
+			errMsg:  "role: invalid role.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.HouseholdMemberRoleValidate(tt.request)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("HouseholdMemberRoleValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("HouseholdMemberRoleValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("HouseholdMemberRoleValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
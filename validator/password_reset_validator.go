@@ -0,0 +1,44 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+type IPasswordResetValidator interface {
+	ForgotPasswordValidate(request model.ForgotPasswordRequest) error
+	ResetPasswordValidate(request model.ResetPasswordRequest) error
+}
+
+type passwordResetValidator struct{}
+
+func NewPasswordResetValidator() IPasswordResetValidator {
+	return &passwordResetValidator{}
+}
+
+func (pv *passwordResetValidator) ForgotPasswordValidate(request model.ForgotPasswordRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.Email,
+			validation.Required.Error("email is required"),
+			validation.RuneLength(1, 30).Error("limited max 30 char"),
+			is.EmailFormat.Error("is not valid email format"),
+		),
+	)
+}
+
+func (pv *passwordResetValidator) ResetPasswordValidate(request model.ResetPasswordRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.Token,
+			validation.Required.Error("token is required"),
+		),
+		validation.Field(
+			&request.Password,
+			validation.Required.Error("password is required"),
+			validation.RuneLength(6, 30).Error("limited min 6 max 30 char"),
+		),
+	)
+}
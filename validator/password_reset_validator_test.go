@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+	"testing"
+)
+
+func TestPasswordResetValidator_ForgotPasswordValidate(t *testing.T) {
+	validator := NewPasswordResetValidator()
+
+	tests := []struct {
+		name    string
+		request model.ForgotPasswordRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "有効なメールアドレス",
+			request: model.ForgotPasswordRequest{Email: "user@example.com"},
+			wantErr: false,
+		},
+		{
+			name:    "メールアドレスが空",
+			request: model.ForgotPasswordRequest{},
+			wantErr: true,
+			errMsg:  "email: email is required.",
+		},
+		{
+			name:    "無効なメールアドレス",
+			request: model.ForgotPasswordRequest{Email: "user"},
+			wantErr: true,
+			errMsg:  "email: is not valid email format.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ForgotPasswordValidate(tt.request)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ForgotPasswordValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("ForgotPasswordValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("ForgotPasswordValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestPasswordResetValidator_ResetPasswordValidate(t *testing.T) {
+	validator := NewPasswordResetValidator()
+
+	tests := []struct {
+		name    string
+		request model.ResetPasswordRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "有効なリクエスト",
+			request: model.ResetPasswordRequest{Token: "token", Password: "newpassword"},
+			wantErr: false,
+		},
+		{
+			name:    "トークンが空",
+			request: model.ResetPasswordRequest{Password: "newpassword"},
+			wantErr: true,
+			errMsg:  "token: token is required.",
+		},
+		{
+			name:    "パスワードが短すぎる",
+			request: model.ResetPasswordRequest{Token: "token", Password: "12345"},
+			wantErr: true,
+			errMsg:  "password: limited min 6 max 30 char.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ResetPasswordValidate(tt.request)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ResetPasswordValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("ResetPasswordValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("ResetPasswordValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
@@ -2,12 +2,18 @@ package validator
 
 import (
 	"expiry_tracker/model"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
 type IProductValidator interface {
 	ProductValidate(product model.Product) error
+	ProductQueryValidate(query model.ProductQuery) error
+	QuantityAdjustmentValidate(adjustment model.QuantityAdjustment) error
+	OpenProductValidate(request model.OpenProductRequest) error
+	FreezeProductValidate(request model.FreezeProductRequest) error
+	ThawProductValidate(request model.ThawProductRequest) error
 }
 
 type productValidator struct{}
@@ -37,5 +43,118 @@ func (pv *productValidator) ProductValidate(product model.Product) error {
 			validation.Required.Error("type is required"),
 			validation.In(model.ExpiryTypeBestBefore, model.ExpiryTypeUseBy).Error("invalid type"),
 		),
+		validation.Field(
+			&product.OpenedAt,
+			validation.Max(time.Now()).Error("opened_at must not be in the future"),
+		),
+		validation.Field(
+			&product.DaysAfterOpening,
+			validation.NilOrNotEmpty.Error("days_after_opening must be greater than 0"),
+			validation.Min(1).Error("days_after_opening must be greater than 0"),
+		),
+		validation.Field(
+			&product.CategoryId,
+			validation.NilOrNotEmpty.Error("invalid category"),
+		),
+		validation.Field(
+			&product.StorageLocationId,
+			validation.NilOrNotEmpty.Error("invalid storage location"),
+		),
+	)
+}
+
+func (pv *productValidator) ProductQueryValidate(query model.ProductQuery) error {
+	return validation.ValidateStruct(&query,
+		validation.Field(
+			&query.ExpiryFrom,
+			validation.Date("2006-01-02").Error("must be YYYY-MM-DD"),
+		),
+		validation.Field(
+			&query.ExpiryTo,
+			validation.Date("2006-01-02").Error("must be YYYY-MM-DD"),
+		),
+		validation.Field(
+			&query.Type,
+			validation.In(model.ExpiryTypeBestBefore, model.ExpiryTypeUseBy).Error("invalid type"),
+		),
+		validation.Field(
+			&query.Status,
+			validation.In(model.ExpiryStatusFresh, model.ExpiryStatusExpiringSoon, model.ExpiryStatusExpired).Error("invalid status"),
+		),
+		validation.Field(
+			&query.Q,
+			validation.RuneLength(0, 30).Error("limited max 30 char"),
+		),
+		validation.Field(
+			&query.Sort,
+			validation.In(model.ProductSortCreatedAt, model.ProductSortExpiryDate, model.ProductSortName, model.ProductSortQuantity).Error("invalid sort"),
+		),
+		validation.Field(
+			&query.Order,
+			validation.In(model.SortOrderAsc, model.SortOrderDesc).Error("invalid order"),
+		),
+		validation.Field(
+			&query.Page,
+			validation.Min(1).Error("page must be greater than 0"),
+		),
+		validation.Field(
+			&query.PerPage,
+			validation.Min(1).Error("per_page must be greater than 0"),
+			validation.Max(model.MaxProductPerPage).Error("per_page must be 100 or less"),
+		),
+	)
+}
+
+func (pv *productValidator) QuantityAdjustmentValidate(adjustment model.QuantityAdjustment) error {
+	return validation.ValidateStruct(&adjustment,
+		validation.Field(
+			&adjustment.Amount,
+			validation.Min(1).Error("amount must be greater than 0"),
+		),
+	)
+}
+
+func (pv *productValidator) OpenProductValidate(request model.OpenProductRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.OpenedAt,
+			validation.Max(time.Now()).Error("opened_at must not be in the future"),
+		),
+		validation.Field(
+			&request.DaysAfterOpening,
+			validation.NilOrNotEmpty.Error("days_after_opening must be greater than 0"),
+			validation.Min(1).Error("days_after_opening must be greater than 0"),
+		),
+	)
+}
+
+func (pv *productValidator) FreezeProductValidate(request model.FreezeProductRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.FrozenAt,
+			validation.Max(time.Now()).Error("frozen_at must not be in the future"),
+		),
+		validation.Field(
+			&request.StorageLocationId,
+			validation.NilOrNotEmpty.Error("invalid storage location"),
+		),
+		validation.Field(
+			&request.FreezerShelfLifeDays,
+			validation.NilOrNotEmpty.Error("freezer_shelf_life_days must be greater than 0"),
+			validation.Min(1).Error("freezer_shelf_life_days must be greater than 0"),
+		),
+	)
+}
+
+func (pv *productValidator) ThawProductValidate(request model.ThawProductRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.ThawedAt,
+			validation.Max(time.Now()).Error("thawed_at must not be in the future"),
+		),
+		validation.Field(
+			&request.StorageLocationId,
+			validation.NilOrNotEmpty.Error("invalid storage location"),
+		),
 	)
 }
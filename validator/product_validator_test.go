@@ -112,6 +112,30 @@ func TestProductValidator_ProductValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "カテゴリと保存場所を指定",
+			product: model.Product{
+				Name:              "テスト商品",
+				Quantity:          1,
+				ExpiryDate:        time.Now().AddDate(0, 0, 7),
+				Type:              model.ExpiryTypeBestBefore,
+				CategoryId:        uintPtr(1),
+				StorageLocationId: uintPtr(2),
+			},
+			wantErr: false,
+		},
+		{
+			name: "カテゴリIDが0",
+			product: model.Product{
+				Name:       "テスト商品",
+				Quantity:   1,
+				ExpiryDate: time.Now().AddDate(0, 0, 7),
+				Type:       model.ExpiryTypeBestBefore,
+				CategoryId: uintPtr(0),
+			},
+			wantErr: true,
+			errMsg:  "category_id: invalid category.",
+		},
 		{
 			name: "最大文字数ちょうど（30文字）",
 			product: model.Product{
@@ -172,3 +196,288 @@ func TestProductValidator_EdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestProductValidator_ProductQueryValidate(t *testing.T) {
+	validator := NewProductValidator()
+
+	tests := []struct {
+		name    string
+		query   model.ProductQuery
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "空のクエリ",
+			query:   model.ProductQuery{},
+			wantErr: false,
+		},
+		{
+			name: "すべて指定",
+			query: model.ProductQuery{
+				ExpiryFrom: "2025-01-01",
+				ExpiryTo:   "2025-01-31",
+				Type:       model.ExpiryTypeUseBy,
+				Status:     model.ExpiryStatusExpiringSoon,
+				Q:          "牛乳",
+				Sort:       model.ProductSortExpiryDate,
+				Order:      model.SortOrderDesc,
+				Page:       2,
+				PerPage:    100,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "日付形式が不正",
+			query:   model.ProductQuery{ExpiryFrom: "2025/01/01"},
+			wantErr: true,
+			errMsg:  "expiry_from: must be YYYY-MM-DD.",
+		},
+		{
+			name:    "無効なステータス",
+			query:   model.ProductQuery{Status: "rotten"},
+			wantErr: true,
+			errMsg:  "status: invalid status.",
+		},
+		{
+			name:    "無効なソート",
+			query:   model.ProductQuery{Sort: "user_id"},
+			wantErr: true,
+			errMsg:  "sort: invalid sort.",
+		},
+		{
+			name:    "無効な並び順",
+			query:   model.ProductQuery{Order: "up"},
+			wantErr: true,
+			errMsg:  "order: invalid order.",
+		},
+		{
+			name:    "ページが負の値",
+			query:   model.ProductQuery{Page: -1},
+			wantErr: true,
+			errMsg:  "page: page must be greater than 0.",
+		},
+		{
+			name:    "件数が上限超過",
+			query:   model.ProductQuery{PerPage: 101},
+			wantErr: true,
+			errMsg:  "per_page: per_page must be 100 or less.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ProductQueryValidate(tt.query)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ProductQueryValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("ProductQueryValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("ProductQueryValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestProductValidator_QuantityAdjustmentValidate(t *testing.T) {
+	validator := NewProductValidator()
+
+	tests := []struct {
+		name       string
+		adjustment model.QuantityAdjustment
+		wantErr    bool
+		errMsg     string
+	}{
+		{
+			name:       "省略（既定値を使う）",
+			adjustment: model.QuantityAdjustment{},
+			wantErr:    false,
+		},
+		{
+			name:       "正の値",
+			adjustment: model.QuantityAdjustment{Amount: 2},
+			wantErr:    false,
+		},
+		{
+			name:       "負の値",
+			adjustment: model.QuantityAdjustment{Amount: -1},
+			wantErr:    true,
+			errMsg:     "amount: amount must be greater than 0.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.QuantityAdjustmentValidate(tt.adjustment)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("QuantityAdjustmentValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("QuantityAdjustmentValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("QuantityAdjustmentValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestProductValidator_OpenProductValidate(t *testing.T) {
+	validator := NewProductValidator()
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().AddDate(0, 0, 1)
+
+	tests := []struct {
+		name    string
+		request model.OpenProductRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "省略（現在時刻で開封）",
+			request: model.OpenProductRequest{},
+			wantErr: false,
+		},
+		{
+			name:    "開封日時と日数を指定",
+			request: model.OpenProductRequest{OpenedAt: &past, DaysAfterOpening: intPtr(3)},
+			wantErr: false,
+		},
+		{
+			name:    "未来の開封日時",
+			request: model.OpenProductRequest{OpenedAt: &future},
+			wantErr: true,
+			errMsg:  "opened_at: opened_at must not be in the future.",
+		},
+		{
+			name:    "日数が0",
+			request: model.OpenProductRequest{DaysAfterOpening: intPtr(0)},
+			wantErr: true,
+			errMsg:  "days_after_opening: days_after_opening must be greater than 0.",
+		},
+		{
+			name:    "日数が負の値",
+			request: model.OpenProductRequest{DaysAfterOpening: intPtr(-1)},
+			wantErr: true,
+			errMsg:  "days_after_opening: days_after_opening must be greater than 0.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.OpenProductValidate(tt.request)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("OpenProductValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("OpenProductValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("OpenProductValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestProductValidator_FreezeThawValidate(t *testing.T) {
+	validator := NewProductValidator()
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().AddDate(0, 0, 1)
+
+	tests := []struct {
+		name    string
+		check   func() error
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "冷凍（省略）",
+			check:   func() error { return validator.FreezeProductValidate(model.FreezeProductRequest{}) },
+			wantErr: false,
+		},
+		{
+			name: "冷凍（すべて指定）",
+			check: func() error {
+				return validator.FreezeProductValidate(model.FreezeProductRequest{FrozenAt: &past, StorageLocationId: uintPtr(1), FreezerShelfLifeDays: intPtr(30)})
+			},
+			wantErr: false,
+		},
+		{
+			name:    "未来の冷凍日時",
+			check:   func() error { return validator.FreezeProductValidate(model.FreezeProductRequest{FrozenAt: &future}) },
+			wantErr: true,
+			errMsg:  "frozen_at: frozen_at must not be in the future.",
+		},
+		{
+			name: "冷凍保存の日数が0",
+			check: func() error {
+				return validator.FreezeProductValidate(model.FreezeProductRequest{FreezerShelfLifeDays: intPtr(0)})
+			},
+			wantErr: true,
+			errMsg:  "freezer_shelf_life_days: freezer_shelf_life_days must be greater than 0.",
+		},
+		{
+			name:    "解凍（省略）",
+			check:   func() error { return validator.ThawProductValidate(model.ThawProductRequest{}) },
+			wantErr: false,
+		},
+		{
+			name:    "未来の解凍日時",
+			check:   func() error { return validator.ThawProductValidate(model.ThawProductRequest{ThawedAt: &future}) },
+			wantErr: true,
+			errMsg:  "thawed_at: thawed_at must not be in the future.",
+		},
+		{
+			name: "保存場所IDが0",
+			check: func() error {
+				return validator.ThawProductValidate(model.ThawProductRequest{StorageLocationId: uintPtr(0)})
+			},
+			wantErr: true,
+			errMsg:  "storage_location_id: invalid storage location.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type IStatsValidator interface {
+	StatsQueryValidate(query model.StatsQuery) error
+}
+
+type statsValidator struct{}
+
+func NewStatsValidator() IStatsValidator {
+	return &statsValidator{}
+}
+
+func (sv *statsValidator) StatsQueryValidate(query model.StatsQuery) error {
+	return validation.ValidateStruct(&query,
+		validation.Field(
+			&query.Period,
+			validation.In(model.StatsPeriodWeek, model.StatsPeriodMonth).Error("invalid period"),
+		),
+		validation.Field(
+			&query.From,
+			validation.Date("2006-01-02").Error("must be YYYY-MM-DD"),
+		),
+	)
+}
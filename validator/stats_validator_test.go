@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+	"testing"
+)
+
+func TestStatsValidator_StatsQueryValidate(t *testing.T) {
+	validator := NewStatsValidator()
+
+	tests := []struct {
+		name    string
+		query   model.StatsQuery
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "空のクエリ",
+			query:   model.StatsQuery{},
+			wantErr: false,
+		},
+		{
+			name:    "月単位",
+			query:   model.StatsQuery{Period: model.StatsPeriodMonth, From: "2025-01-01"},
+			wantErr: false,
+		},
+		{
+			name:    "無効な期間",
+			query:   model.StatsQuery{Period: "day"},
+			wantErr: true,
+			errMsg:  "period: invalid period.",
+		},
+		{
+			name:    "日付形式が不正",
+			query:   model.StatsQuery{From: "20250101"},
+			wantErr: true,
+			errMsg:  "from: must be YYYY-MM-DD.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.StatsQueryValidate(tt.query)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("StatsQueryValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("StatsQueryValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("StatsQueryValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type IStorageLocationValidator interface {
+	StorageLocationValidate(location model.StorageLocation) error
+}
+
+type storageLocationValidator struct{}
+
+func NewStorageLocationValidator() IStorageLocationValidator {
+	return &storageLocationValidator{}
+}
+
+func (lv *storageLocationValidator) StorageLocationValidate(location model.StorageLocation) error {
+	return validation.ValidateStruct(&location,
+		validation.Field(
+			&location.Name,
+			validation.Required.Error("name is required"),
+			validation.RuneLength(1, 30).Error("limited max 30 char"),
+		),
+		validation.Field(
+			&location.Kind,
+			validation.Required.Error("kind is required"),
+			validation.In(
+				model.StorageLocationKindFridge,
+				model.StorageLocationKindFreezer,
+				model.StorageLocationKindPantry,
+				model.StorageLocationKindOther,
+			).Error("invalid kind"),
+		),
+	)
+}
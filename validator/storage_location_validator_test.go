@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+	"testing"
+)
+
+func TestStorageLocationValidator_StorageLocationValidate(t *testing.T) {
+	validator := NewStorageLocationValidator()
+
+	tests := []struct {
+		name     string
+		location model.StorageLocation
+		wantErr  bool
+		errMsg   string
+	}{
+		{
+			name:     "正常な保存場所",
+			location: model.StorageLocation{Name: "冷凍庫", Kind: model.StorageLocationKindFreezer},
+			wantErr:  false,
+		},
+		{
+			name:     "名前が空",
+			location: model.StorageLocation{Name: "", Kind: model.StorageLocationKindFridge},
+			wantErr:  true,
+			errMsg:   "name: name is required.",
+		},
+		{
+			name:     "種類が空",
+			location: model.StorageLocation{Name: "棚"},
+			wantErr:  true,
+			errMsg:   "kind: kind is required.",
+		},
+		{
+			name:     "無効な種類",
+			location: model.StorageLocation{Name: "棚", Kind: "garage"},
+			wantErr:  true,
+			errMsg:   "kind: invalid kind.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.StorageLocationValidate(tt.location)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("StorageLocationValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("StorageLocationValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("StorageLocationValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
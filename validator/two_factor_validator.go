@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type ITwoFactorValidator interface {
+	TwoFactorCodeValidate(request model.TwoFactorCodeRequest) error
+	TwoFactorDisableValidate(request model.TwoFactorDisableRequest) error
+	TwoFactorLoginValidate(request model.TwoFactorLoginRequest) error
+}
+
+type twoFactorValidator struct{}
+
+func NewTwoFactorValidator() ITwoFactorValidator {
+	return &twoFactorValidator{}
+}
+
+func (tv *twoFactorValidator) TwoFactorCodeValidate(request model.TwoFactorCodeRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.Code,
+			validation.Required.Error("code is required"),
+			validation.RuneLength(6, 20).Error("limited min 6 max 20 char"),
+		),
+	)
+}
+
+func (tv *twoFactorValidator) TwoFactorDisableValidate(request model.TwoFactorDisableRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.CurrentPassword,
+			validation.Required.Error("current_password is required"),
+		),
+		validation.Field(
+			&request.Code,
+			validation.Required.Error("code is required"),
+			validation.RuneLength(6, 20).Error("limited min 6 max 20 char"),
+		),
+	)
+}
+
+func (tv *twoFactorValidator) TwoFactorLoginValidate(request model.TwoFactorLoginRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.ChallengeToken,
+			validation.Required.Error("challenge_token is required"),
+		),
+		validation.Field(
+			&request.Code,
+			validation.Required.Error("code is required"),
+			validation.RuneLength(6, 20).Error("limited min 6 max 20 char"),
+		),
+	)
+}
@@ -0,0 +1,153 @@
+package validator
+
+import (
+	"expiry_tracker/model"
+	"testing"
+)
+
+func TestTwoFactorValidator_TwoFactorCodeValidate(t *testing.T) {
+	validator := NewTwoFactorValidator()
+
+	tests := []struct {
+		name    string
+		request model.TwoFactorCodeRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "有効な認証コード",
+			request: model.TwoFactorCodeRequest{Code: "123456"},
+			wantErr: false,
+		},
+		{
+			name:    "認証コードが空",
+			request: model.TwoFactorCodeRequest{},
+			wantErr: true,
+			errMsg:  "code: code is required.",
+		},
+		{
+			name:    "認証コードが短すぎる",
+			request: model.TwoFactorCodeRequest{Code: "12345"},
+			wantErr: true,
+			errMsg:  "code: limited min 6 max 20 char.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.TwoFactorCodeValidate(tt.request)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("TwoFactorCodeValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("TwoFactorCodeValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("TwoFactorCodeValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestTwoFactorValidator_TwoFactorDisableValidate(t *testing.T) {
+	validator := NewTwoFactorValidator()
+
+	tests := []struct {
+		name    string
+		request model.TwoFactorDisableRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "有効なリクエスト",
+			request: model.TwoFactorDisableRequest{CurrentPassword: "password", Code: "abcde-fghij"},
+			wantErr: false,
+		},
+		{
+			name:    "現在のパスワードが空",
+			request: model.TwoFactorDisableRequest{Code: "123456"},
+			wantErr: true,
+			errMsg:  "current_password: current_password is required.",
+		},
+		{
+			name:    "認証コードが空",
+			request: model.TwoFactorDisableRequest{CurrentPassword: "password"},
+			wantErr: true,
+			errMsg:  "code: code is required.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.TwoFactorDisableValidate(tt.request)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("TwoFactorDisableValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("TwoFactorDisableValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("TwoFactorDisableValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestTwoFactorValidator_TwoFactorLoginValidate(t *testing.T) {
+	validator := NewTwoFactorValidator()
+
+	tests := []struct {
+		name    string
+		request model.TwoFactorLoginRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "有効なリクエスト",
+			request: model.TwoFactorLoginRequest{ChallengeToken: "token", Code: "123456"},
+			wantErr: false,
+		},
+		{
+			name:    "チャレンジトークンが空",
+			request: model.TwoFactorLoginRequest{Code: "123456"},
+			wantErr: true,
+			errMsg:  "challenge_token: challenge_token is required.",
+		},
+		{
+			name:    "認証コードが長すぎる",
+			request: model.TwoFactorLoginRequest{ChallengeToken: "token", Code: "123456789012345678901"},
+			wantErr: true,
+			errMsg:  "code: limited min 6 max 20 char.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.TwoFactorLoginValidate(tt.request)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("TwoFactorLoginValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("TwoFactorLoginValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("TwoFactorLoginValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
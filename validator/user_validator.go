@@ -2,6 +2,7 @@ package validator
 
 import (
 	"expiry_tracker/model"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -10,6 +11,11 @@ import (
 type IUserValidator interface {
 	SignUpUserValidate(user model.User) error
 	LoginUserValidate(user model.User) error
+	VerifyEmailValidate(request model.VerifyEmailRequest) error
+	UpdateProfileValidate(request model.UpdateProfileRequest) error
+	ChangeEmailValidate(request model.ChangeEmailRequest) error
+	ChangePasswordValidate(request model.ChangePasswordRequest) error
+	DeleteAccountValidate(request model.DeleteAccountRequest) error
 }
 
 type userValidator struct{}
@@ -36,6 +42,10 @@ func (uv *userValidator) SignUpUserValidate(user model.User) error {
 			validation.Required.Error("name is required"),
 			validation.RuneLength(1, 30).Error("limited max 30 char"),
 		),
+		validation.Field(
+			&user.TimeZone,
+			isTimeZone,
+		),
 	)
 }
 
@@ -54,3 +64,79 @@ func (uv *userValidator) LoginUserValidate(user model.User) error {
 		),
 	)
 }
+
+func (uv *userValidator) VerifyEmailValidate(request model.VerifyEmailRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.Token,
+			validation.Required.Error("token is required"),
+		),
+	)
+}
+
+func (uv *userValidator) UpdateProfileValidate(request model.UpdateProfileRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.Name,
+			validation.Required.Error("name is required"),
+			validation.RuneLength(1, 30).Error("limited max 30 char"),
+		),
+		validation.Field(
+			&request.TimeZone,
+			isTimeZone,
+		),
+	)
+}
+
+func (uv *userValidator) ChangeEmailValidate(request model.ChangeEmailRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.Email,
+			validation.Required.Error("email is required"),
+			validation.RuneLength(1, 30).Error("limited max 30 char"),
+			is.Email.Error("is not valid email format"),
+		),
+		validation.Field(
+			&request.CurrentPassword,
+			validation.Required.Error("current_password is required"),
+		),
+	)
+}
+
+func (uv *userValidator) ChangePasswordValidate(request model.ChangePasswordRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.CurrentPassword,
+			validation.Required.Error("current_password is required"),
+		),
+		validation.Field(
+			&request.NewPassword,
+			validation.Required.Error("new_password is required"),
+			validation.RuneLength(6, 30).Error("limited min 6 max 30 char"),
+		),
+	)
+}
+
+func (uv *userValidator) DeleteAccountValidate(request model.DeleteAccountRequest) error {
+	return validation.ValidateStruct(&request,
+		validation.Field(
+			&request.CurrentPassword,
+			validation.Required.Error("current_password is required"),
+		),
+	)
+}
+
+var isTimeZone = validation.NewStringRuleWithError(
+	validTimeZone,
+	validation.NewError("validation_is_time_zone", "is not valid time zone"),
+)
+
+// validTimeZone reports whether name is an IANA time zone. "" and "Local" are
+// accepted by time.LoadLocation but mean UTC and the server's zone.
+func validTimeZone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
@@ -121,6 +121,38 @@ func TestUserValidator_SignUpUserValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "有効なタイムゾーン",
+			user: model.User{
+				Email:    "test@example.com",
+				Password: "password123",
+				Name:     "テストユーザー",
+				TimeZone: "America/New_York",
+			},
+			wantErr: false,
+		},
+		{
+			name: "無効なタイムゾーン",
+			user: model.User{
+				Email:    "test@example.com",
+				Password: "password123",
+				Name:     "テストユーザー",
+				TimeZone: "Mars/Olympus_Mons",
+			},
+			wantErr: true,
+			errMsg:  "time_zone: is not valid time zone.",
+		},
+		{
+			name: "サーバーのタイムゾーン",
+			user: model.User{
+				Email:    "test@example.com",
+				Password: "password123",
+				Name:     "テストユーザー",
+				TimeZone: "Local",
+			},
+			wantErr: true,
+			errMsg:  "time_zone: is not valid time zone.",
+		},
 	}
 
 	for _, tt := range tests {
@@ -299,3 +331,162 @@ func TestUserValidator_EdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestUserValidator_VerifyEmailValidate(t *testing.T) {
+	validator := NewUserValidator()
+
+	if err := validator.VerifyEmailValidate(model.VerifyEmailRequest{Token: "token"}); err != nil {
+		t.Errorf("VerifyEmailValidate() error = %v, wantErr false", err)
+	}
+	err := validator.VerifyEmailValidate(model.VerifyEmailRequest{})
+	if err == nil || err.Error() != "token: token is required." {
+		t.Errorf("VerifyEmailValidate() error = %v, wantErrMsg %v", err, "token: token is required.")
+	}
+}
+
+func TestUserValidator_UpdateProfileValidate(t *testing.T) {
+	validator := NewUserValidator()
+
+	tests := []struct {
+		name    string
+		request model.UpdateProfileRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "名前とタイムゾーン",
+			request: model.UpdateProfileRequest{Name: "新しい名前", TimeZone: "America/New_York"},
+			wantErr: false,
+		},
+		{
+			name:    "タイムゾーン省略",
+			request: model.UpdateProfileRequest{Name: "新しい名前"},
+			wantErr: false,
+		},
+		{
+			name:    "名前が空",
+			request: model.UpdateProfileRequest{},
+			wantErr: true,
+			errMsg:  "name: name is required.",
+		},
+		{
+			name:    "無効なタイムゾーン",
+			request: model.UpdateProfileRequest{Name: "新しい名前", TimeZone: "Mars/Olympus"},
+			wantErr: true,
+			errMsg:  "time_zone: is not valid time zone.",
+		},
+		{
+			name:    "サーバーのタイムゾーン",
+			request: model.UpdateProfileRequest{Name: "新しい名前", TimeZone: "Local"},
+			wantErr: true,
+			errMsg:  "time_zone: is not valid time zone.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.UpdateProfileValidate(tt.request)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("UpdateProfileValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("UpdateProfileValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("UpdateProfileValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestValidTimeZone(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeZone string
+		want     bool
+	}{
+		{name: "IANA のタイムゾーン", timeZone: "Asia/Tokyo", want: true},
+		{name: "UTC", timeZone: "UTC", want: true},
+		{name: "空文字", timeZone: "", want: false},
+		{name: "サーバーのタイムゾーン", timeZone: "Local", want: false},
+		{name: "存在しないタイムゾーン", timeZone: "Mars/Olympus", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validTimeZone(tt.timeZone); got != tt.want {
+				t.Errorf("validTimeZone(%q) = %v, want %v", tt.timeZone, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserValidator_ChangePasswordValidate(t *testing.T) {
+	validator := NewUserValidator()
+
+	tests := []struct {
+		name    string
+		request model.ChangePasswordRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "有効なリクエスト",
+			request: model.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "newpassword"},
+			wantErr: false,
+		},
+		{
+			name:    "現在のパスワードが空",
+			request: model.ChangePasswordRequest{NewPassword: "newpassword"},
+			wantErr: true,
+			errMsg:  "current_password: current_password is required.",
+		},
+		{
+			name:    "新しいパスワードが短すぎる",
+			request: model.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "12345"},
+			wantErr: true,
+			errMsg:  "new_password: limited min 6 max 30 char.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ChangePasswordValidate(tt.request)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ChangePasswordValidate() error = nil, wantErr %v", tt.wantErr)
+					return
+				}
+				if tt.errMsg != "" && err.Error() != tt.errMsg {
+					t.Errorf("ChangePasswordValidate() error = %v, wantErrMsg %v", err.Error(), tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("ChangePasswordValidate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestUserValidator_CurrentPasswordRequired(t *testing.T) {
+	validator := NewUserValidator()
+
+	err := validator.ChangeEmailValidate(model.ChangeEmailRequest{Email: "test"})
+	if err == nil || err.Error() != "current_password: current_password is required; email: is not valid email format." {
+		t.Errorf("ChangeEmailValidate() error = %v", err)
+	}
+	err = validator.DeleteAccountValidate(model.DeleteAccountRequest{})
+	if err == nil || err.Error() != "current_password: current_password is required." {
+		t.Errorf("DeleteAccountValidate() error = %v", err)
+	}
+	if err := validator.DeleteAccountValidate(model.DeleteAccountRequest{CurrentPassword: "password123"}); err != nil {
+		t.Errorf("DeleteAccountValidate() error = %v, wantErr false", err)
+	}
+}
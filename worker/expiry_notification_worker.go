@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"context"
+	"expiry_tracker/usecase"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+type IWorker interface {
+	Run(ctx context.Context)
+	// LastRun returns when the worker last finished a pass, or the zero time
+	// before its first one. Readiness checks use it as a heartbeat.
+	LastRun() time.Time
+	Interval() time.Duration
+}
+
+type expiryNotificationWorker struct {
+	nu       usecase.INotificationUsecase
+	interval time.Duration
+	lastRun  atomic.Int64 // UnixNano
+}
+
+func NewExpiryNotificationWorker(nu usecase.INotificationUsecase, interval time.Duration) IWorker {
+	return &expiryNotificationWorker{nu: nu, interval: interval}
+}
+
+// Run scans for expiring products once immediately and then on every tick
+// until ctx is cancelled.
+func (w *expiryNotificationWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.nu.NotifyExpiringProducts(); err != nil {
+			log.Printf("expiry notification failed: %v", err)
+		}
+		// 失敗してもループが動いていることを示す（データベースの状態は別に確認する）
+		w.lastRun.Store(time.Now().UnixNano())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *expiryNotificationWorker) LastRun() time.Time {
+	n := w.lastRun.Load()
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+func (w *expiryNotificationWorker) Interval() time.Duration {
+	return w.interval
+}